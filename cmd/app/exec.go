@@ -7,10 +7,17 @@ package app
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/gardener/docforge/pkg/manifest"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/web"
 	documentworker "github.com/gardener/docforge/pkg/workers/document"
 	"github.com/gardener/docforge/pkg/workers/downloader"
 	"github.com/gardener/docforge/pkg/workers/githubinfo"
@@ -40,6 +47,12 @@ func exec(ctx context.Context) error {
 
 	config := getReactorConfig(options.Options, options.Hugo, rhs)
 	manifestURL := options.ManifestPath
+	if u, err := url.Parse(manifestURL); err == nil && u.Scheme == "" {
+		// local manifest files are resolved by their absolute path
+		if manifestURL, err = filepath.Abs(manifestURL); err != nil {
+			return err
+		}
+	}
 	var (
 		ghInfo      githubinfo.GitHubInfo
 		ghInfoTasks taskqueue.QueueController
@@ -47,7 +60,14 @@ func exec(ctx context.Context) error {
 	reactorWG := &sync.WaitGroup{}
 
 	rhRegistry := repositoryhosts.NewRegistry(config.RepositoryHosts...)
-	documentNodes, err := manifest.ResolveManifest(manifestURL, rhRegistry)
+	// plain web URLs are read as manifests and document sources only, links to them are kept external
+	sourceRegistry := repositoryhosts.NewRegistry(append(slices.Clone(config.RepositoryHosts), web.New(http.DefaultClient))...)
+	var documentNodes []*manifest.Node
+	if interpolation := manifestInterpolation(config.Options); interpolation != nil {
+		documentNodes, err = manifest.ResolveManifestWithVars(manifestURL, sourceRegistry, *interpolation)
+	} else {
+		documentNodes, err = manifest.ResolveManifest(manifestURL, sourceRegistry)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to resolve manifest %s. %+v", config.ManifestPath, err)
 	}
@@ -55,18 +75,28 @@ func exec(ctx context.Context) error {
 		fmt.Println(documentNodes[0])
 	}
 
-	dScheduler, downloadTasks, err := downloader.New(config.ResourceDownloadWorkersCount, config.FailFast, reactorWG, rhRegistry, config.ResourceDownloadWriter)
+	dScheduler, downloadTasks, err := downloader.New(config.ResourceDownloadWorkersCount, config.FailFast, reactorWG, rhRegistry, config.ResourceDownloadWriter, config.ResumeDownloads)
+	if err != nil {
+		return err
+	}
+	vWorker, err := linkvalidator.NewValidatorWorker(rhRegistry)
 	if err != nil {
 		return err
 	}
-	v, validatorTasks, err := linkvalidator.New(config.ValidationWorkersCount, config.FailFast, reactorWG, rhRegistry)
+	if config.ValidateLinks {
+		if err = loadValidationCaches(vWorker, config.Options); err != nil {
+			return err
+		}
+		defer saveValidationCaches(vWorker, config.Options)
+	}
+	v, validatorTasks, err := linkvalidator.NewFromWorker(config.ValidationWorkersCount, config.FailFast, reactorWG, vWorker)
 	if err != nil {
 		return err
 	}
 	if !config.ValidateLinks {
 		v = nil
 	}
-	docProcessor, docTasks, err := documentworker.New(config.DocumentWorkersCount, config.FailFast, reactorWG, documentNodes, config.ResourcesPath, dScheduler, v, rhRegistry, config.Hugo, config.Writer)
+	docProcessor, docTasks, err := documentworker.New(config.DocumentWorkersCount, config.FailFast, reactorWG, documentNodes, config.ResourcesPath, dScheduler, v, rhRegistry, sourceRegistry, config.Hugo, config.Writer)
 	if err != nil {
 		return err
 	}
@@ -74,7 +104,7 @@ func exec(ctx context.Context) error {
 	qcc := taskqueue.NewQueueControllerCollection(reactorWG, downloadTasks, validatorTasks, docTasks)
 
 	if config.GitInfoWriter != nil {
-		ghInfo, ghInfoTasks, err = githubinfo.New(config.ResourceDownloadWorkersCount, config.FailFast, reactorWG, rhRegistry, config.GitInfoWriter)
+		ghInfo, ghInfoTasks, err = githubinfo.New(config.ResourceDownloadWorkersCount, config.FailFast, reactorWG, sourceRegistry, config.GitInfoWriter)
 		if err != nil {
 			return err
 		}
@@ -102,3 +132,52 @@ func exec(ctx context.Context) error {
 	rhRegistry.LogRateLimits(ctx)
 	return qcc.GetErrorList().ErrorOrNil()
 }
+
+// loadValidationCaches loads the link validation caches of previous runs that are configured
+func loadValidationCaches(vWorker *linkvalidator.ValidatorWorker, options Options) error {
+	var err error
+	if options.ValidationETagCache != "" {
+		if vWorker.ETags, err = linkvalidator.LoadETagCache(options.ValidationETagCache); err != nil {
+			return err
+		}
+	}
+	if options.ValidatedLinksCache != "" {
+		return vWorker.LoadValidated(options.ValidatedLinksCache, options.ValidatedLinksTTL)
+	}
+	return nil
+}
+
+// saveValidationCaches writes the link validation caches for the next runs,
+// failures are logged only as the caches are an optimization
+func saveValidationCaches(vWorker *linkvalidator.ValidatorWorker, options Options) {
+	if vWorker.ETags != nil {
+		if err := vWorker.ETags.Save(); err != nil {
+			klog.Warningf("saving link validation ETag cache failed: %v", err)
+		}
+	}
+	if options.ValidatedLinksCache != "" {
+		if err := vWorker.SaveValidated(options.ValidatedLinksCache); err != nil {
+			klog.Warningf("saving validated links failed: %v", err)
+		}
+	}
+}
+
+// manifestInterpolation returns the interpolation of the variable references in the manifests
+// configured by the options, nil if only the variables declared by the manifests are substituted
+func manifestInterpolation(options Options) *manifest.Interpolation {
+	if len(options.ManifestVars) == 0 && !options.ManifestEnv && !options.ManifestVarsStrict {
+		return nil
+	}
+	vars := map[string]string{}
+	if options.ManifestEnv {
+		for _, env := range os.Environ() {
+			if k, v, ok := strings.Cut(env, "="); ok {
+				vars[k] = v
+			}
+		}
+	}
+	for k, v := range options.ManifestVars {
+		vars[k] = v
+	}
+	return &manifest.Interpolation{Vars: vars, Strict: options.ManifestVarsStrict}
+}
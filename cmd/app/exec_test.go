@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/repositoryhostsfakes"
+	"github.com/gardener/docforge/pkg/workers/linkvalidator"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Link validation caches", func() {
+	var (
+		dir     string
+		options Options
+		vWorker *linkvalidator.ValidatorWorker
+	)
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "validation-caches")
+		Expect(err).NotTo(HaveOccurred())
+		options = Options{}
+		vWorker, err = linkvalidator.NewValidatorWorker(&repositoryhostsfakes.FakeRegistry{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+	It("are disabled by default", func() {
+		Expect(loadValidationCaches(vWorker, options)).To(Succeed())
+		Expect(vWorker.ETags).To(BeNil())
+		saveValidationCaches(vWorker, options)
+		entries, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+	It("loads and saves the ETag cache", func() {
+		options.ValidationETagCache = filepath.Join(dir, "etags.json")
+		Expect(os.WriteFile(options.ValidationETagCache, []byte(`{"https://a/b":{"etag":"\"abc\""}}`), 0644)).To(Succeed())
+		Expect(loadValidationCaches(vWorker, options)).To(Succeed())
+		Expect(vWorker.ETags).NotTo(BeNil())
+		Expect(os.Remove(options.ValidationETagCache)).To(Succeed())
+		saveValidationCaches(vWorker, options)
+		cnt, err := os.ReadFile(options.ValidationETagCache)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(cnt)).To(ContainSubstring(`"https://a/b"`))
+	})
+	It("loads and saves the validated links", func() {
+		options.ValidatedLinksCache = filepath.Join(dir, "validated.json")
+		options.ValidatedLinksTTL = time.Hour
+		cnt := fmt.Sprintf(`{"https://a/fresh": %q, "https://a/expired": %q}`,
+			time.Now().Add(-time.Minute).Format(time.RFC3339), time.Now().Add(-2*time.Hour).Format(time.RFC3339))
+		Expect(os.WriteFile(options.ValidatedLinksCache, []byte(cnt), 0644)).To(Succeed())
+		Expect(loadValidationCaches(vWorker, options)).To(Succeed())
+		saveValidationCaches(vWorker, options)
+		links := map[string]time.Time{}
+		saved, err := os.ReadFile(options.ValidatedLinksCache)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(saved, &links)).To(Succeed())
+		Expect(links).To(HaveKey("https://a/fresh"))
+		Expect(links).NotTo(HaveKey("https://a/expired"))
+	})
+	It("reports malformed caches", func() {
+		options.ValidationETagCache = filepath.Join(dir, "etags.json")
+		Expect(os.WriteFile(options.ValidationETagCache, []byte("{"), 0644)).To(Succeed())
+		Expect(loadValidationCaches(vWorker, options)).To(MatchError(ContainSubstring("parsing ETag cache")))
+	})
+})
+
+var _ = Describe("Manifest interpolation", func() {
+	It("substitutes only the manifest variables by default", func() {
+		Expect(manifestInterpolation(Options{})).To(BeNil())
+	})
+	It("uses the configured variables", func() {
+		interpolation := manifestInterpolation(Options{ManifestVars: map[string]string{"ORG": "gardener"}, ManifestVarsStrict: true})
+		Expect(interpolation).NotTo(BeNil())
+		Expect(interpolation.Vars).To(Equal(map[string]string{"ORG": "gardener"}))
+		Expect(interpolation.Strict).To(BeTrue())
+	})
+	It("lets the configured variables override the environment", func() {
+		Expect(os.Setenv("DOCFORGE_TEST_ORG", "env")).To(Succeed())
+		Expect(os.Setenv("DOCFORGE_TEST_REPO", "docforge")).To(Succeed())
+		defer os.Unsetenv("DOCFORGE_TEST_ORG")
+		defer os.Unsetenv("DOCFORGE_TEST_REPO")
+		interpolation := manifestInterpolation(Options{ManifestEnv: true, ManifestVars: map[string]string{"DOCFORGE_TEST_ORG": "gardener"}})
+		Expect(interpolation.Vars).To(HaveKeyWithValue("DOCFORGE_TEST_ORG", "gardener"))
+		Expect(interpolation.Vars).To(HaveKeyWithValue("DOCFORGE_TEST_REPO", "docforge"))
+	})
+})
@@ -7,7 +7,9 @@ package app
 import (
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/githubhttpcache"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +22,18 @@ func configureFlags(command *cobra.Command) {
 		"Manifest path.")
 	_ = vip.BindPFlag("manifest", command.Flags().Lookup("manifest"))
 
+	command.Flags().StringToString("manifest-vars", map[string]string{},
+		"Values of the {{VAR}}, ${VAR} and $VAR references in the manifests, e.g. ORG=gardener. They override the variables declared by the manifests.")
+	_ = vip.BindPFlag("manifest-vars", command.Flags().Lookup("manifest-vars"))
+
+	command.Flags().Bool("manifest-env", false,
+		"Substitutes the {{VAR}}, ${VAR} and $VAR references in the manifests with the environment variables, manifest-vars take precedence.")
+	_ = vip.BindPFlag("manifest-env", command.Flags().Lookup("manifest-env"))
+
+	command.Flags().Bool("manifest-vars-strict", false,
+		"Fails on references to undefined variables in the manifests instead of keeping them as they are.")
+	_ = vip.BindPFlag("manifest-vars-strict", command.Flags().Lookup("manifest-vars-strict"))
+
 	command.Flags().String("resources-download-path", "__resources",
 		"Resources download path.")
 	_ = vip.BindPFlag("resources-download-path", command.Flags().Lookup("resources-download-path"))
@@ -28,10 +42,34 @@ func configureFlags(command *cobra.Command) {
 		"GitHub personal tokens authorizing read access from repositories per GitHub instance. Note that if the GitHub token is already provided by `github-oauth-token` it will be overridden by it.")
 	_ = vip.BindPFlag("github-oauth-token-map", command.Flags().Lookup("github-oauth-token-map"))
 
+	command.Flags().StringToString("github-api-base-url-map", map[string]string{},
+		"GitHub REST API base URLs per GitHub instance host, e.g. github.example.com=https://github.example.com/api/v3/. Instances without an entry use the GitHub Enterprise default <instance>/api/v3/.")
+	_ = vip.BindPFlag("github-api-base-url-map", command.Flags().Lookup("github-api-base-url-map"))
+
+	command.Flags().StringToString("github-upload-url-map", map[string]string{},
+		"GitHub upload API URLs per GitHub instance host. Defaults to the instance API base URL.")
+	_ = vip.BindPFlag("github-upload-url-map", command.Flags().Lookup("github-upload-url-map"))
+
+	command.Flags().String("github-user-agent", "",
+		"User-Agent set on requests to GitHub instances. Defaults to the GitHub client's User-Agent.")
+	_ = vip.BindPFlag("github-user-agent", command.Flags().Lookup("github-user-agent"))
+
+	command.Flags().StringToString("github-headers", map[string]string{},
+		"Additional headers set on requests to GitHub instances (e.g. tracing headers).")
+	_ = vip.BindPFlag("github-headers", command.Flags().Lookup("github-headers"))
+
+	command.Flags().Int("github-rate-limit-reserve", githubhttpcache.DefaultRateLimitReserve,
+		"Number of remaining GitHub API requests at which all workers pause until the rate limit of the GitHub instance is reset.")
+	_ = vip.BindPFlag("github-rate-limit-reserve", command.Flags().Lookup("github-rate-limit-reserve"))
+
 	command.Flags().String("github-info-destination", "",
 		"If specified, docforge will download also additional github info for the files from the documentation structure into this destination.")
 	_ = vip.BindPFlag("github-info-destination", command.Flags().Lookup("github-info-destination"))
 
+	command.Flags().String("github-info-date-format", "",
+		"Go time layout of the lastmod and publishdate github info dates, e.g. 2006-01-02T15:04:05Z07:00 for ISO-8601. Defaults to 2006-01-02 15:04:05.")
+	_ = vip.BindPFlag("github-info-date-format", command.Flags().Lookup("github-info-date-format"))
+
 	command.Flags().Bool("fail-fast", false,
 		"Fail-fast vs fault tolerant operation.")
 	_ = vip.BindPFlag("fail-fast", command.Flags().Lookup("fail-fast"))
@@ -56,6 +94,10 @@ func configureFlags(command *cobra.Command) {
 		"Number of workers downloading document resources in parallel.")
 	_ = vip.BindPFlag("download-workers", command.Flags().Lookup("download-workers"))
 
+	command.Flags().Bool("resume-downloads", false,
+		"Skips downloading document resources that a previous run already wrote with the content of their git blob. Resources written with hashed names are always downloaded.")
+	_ = vip.BindPFlag("resume-downloads", command.Flags().Lookup("resume-downloads"))
+
 	command.Flags().Bool("hugo", false,
 		"Build documentation bundle for hugo.")
 	_ = vip.BindPFlag("hugo", command.Flags().Lookup("hugo"))
@@ -80,6 +122,18 @@ func configureFlags(command *cobra.Command) {
 		"Links should be validated")
 	_ = vip.BindPFlag("validate-links", command.Flags().Lookup("validate-links"))
 
+	command.Flags().String("validation-etag-cache", "",
+		"File caching the ETag and Last-Modified headers of validated links between runs, unchanged links are validated with conditional requests. Disabled if empty.")
+	_ = vip.BindPFlag("validation-etag-cache", command.Flags().Lookup("validation-etag-cache"))
+
+	command.Flags().String("validated-links-cache", "",
+		"File recording the links validated successfully with the time of their validation, the links are not validated again by the next runs within validated-links-ttl. Disabled if empty.")
+	_ = vip.BindPFlag("validated-links-cache", command.Flags().Lookup("validated-links-cache"))
+
+	command.Flags().Duration("validated-links-ttl", 24*time.Hour,
+		"Time after which the links recorded in validated-links-cache are validated again.")
+	_ = vip.BindPFlag("validated-links-ttl", command.Flags().Lookup("validated-links-ttl"))
+
 	cacheDir := ""
 	userHomeDir, err := os.UserHomeDir()
 	if err == nil {
@@ -17,6 +17,7 @@ import (
 	"github.com/gardener/docforge/pkg/manifest"
 	"github.com/gardener/docforge/pkg/osfakes/osshim"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/filesystem"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts/githubhttpcache"
 	"github.com/gardener/docforge/pkg/writers"
 	"github.com/google/go-github/v43/github"
@@ -41,26 +42,33 @@ func initRepositoryHosts(ctx context.Context, o repositoryhosts.RepositoryHostOp
 			continue
 		}
 		cachePath := filepath.Join(o.CacheHomeDir, "diskv", host)
-		client, httpClient, err := buildClient(ctx, oAuthToken, instance, cachePath)
+		client, httpClient, err := buildClient(ctx, oAuthToken, instance, cachePath, o)
 		if err != nil {
 			errs = multierror.Append(errs, err)
 		}
-		rh := newRepositoryHost(u.Host, client, httpClient, o.ResourceMappings, options)
+		rh := newRepositoryHost(u.Host, client, httpClient, o.ResourceMappings, options, o.GitInfoDateFormat)
 		rhs = append(rhs, rh)
 	}
 	if len(rhs) == 0 {
 		return rhs, fmt.Errorf("no resource handlers were loaded. Is the config yaml file correct?")
 	}
+	// local files are handled last, e.g. a manifest given by its file path
+	rhs = append(rhs, filesystem.New())
 	return rhs, errs.ErrorOrNil()
 }
 
-func buildClient(ctx context.Context, accessToken string, host string, cachePath string) (*github.Client, *http.Client, error) {
+func buildClient(ctx context.Context, accessToken string, host string, cachePath string, o repositoryhosts.RepositoryHostOptions) (*github.Client, *http.Client, error) {
 	base := http.DefaultTransport
+	if len(o.Headers) > 0 {
+		base = &headerTransport{headers: o.Headers, base: base}
+	}
 	if len(accessToken) > 0 {
 		// if token provided replace base RoundTripper
 		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken})
-		base = oauth2.NewClient(ctx, ts).Transport
+		base = &oauth2.Transport{Source: ts, Base: base}
 	}
+	// all workers share the rate limit of the instance, cached responses don't consume it
+	base = githubhttpcache.NewRateLimitCoordinator(base, o.RateLimitReserve)
 
 	flatTransform := func(s string) []string { return []string{} }
 	d := diskv.New(diskv.Options{
@@ -77,25 +85,80 @@ func buildClient(ctx context.Context, accessToken string, host string, cachePath
 
 	httpClient := cacheTransport.Client()
 
-	var (
-		client *github.Client
-		err    error
-	)
+	client, err := newGitHubClient(httpClient, host, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(o.UserAgent) > 0 {
+		client.UserAgent = o.UserAgent
+	}
+	return client, httpClient, nil
+}
+
+// newGitHubClient creates a GitHub client for a GitHub instance. If an API base URL is configured
+// for the instance host it is used as is, otherwise the GitHub Enterprise defaults are applied
+func newGitHubClient(httpClient *http.Client, instance string, o repositoryhosts.RepositoryHostOptions) (*github.Client, error) {
+	u, err := url.Parse(instance)
+	if err != nil {
+		return nil, err
+	}
+	apiURL, ok := o.APIBaseURLs[u.Host]
+	if !ok {
+		if instance == "https://github.com" {
+			return github.NewClient(httpClient), nil
+		}
+		return github.NewEnterpriseClient(instance, "", httpClient)
+	}
+	uploadURL, ok := o.UploadURLs[u.Host]
+	if !ok {
+		uploadURL = apiURL
+	}
+	client := github.NewClient(httpClient)
+	if client.BaseURL, err = parseAPIURL(apiURL); err != nil {
+		return nil, fmt.Errorf("invalid API base URL for %s: %w", u.Host, err)
+	}
+	if client.UploadURL, err = parseAPIURL(uploadURL); err != nil {
+		return nil, fmt.Errorf("invalid upload URL for %s: %w", u.Host, err)
+	}
+	return client, nil
+}
+
+// parseAPIURL parses absolute API URL and ensures it has a trailing slash as required by the GitHub client
+func parseAPIURL(apiURL string) (*url.URL, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsAbs() {
+		return nil, fmt.Errorf("%s is not an absolute URL", apiURL)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
+}
+
+// headerTransport sets additional headers on each outgoing request
+type headerTransport struct {
+	headers map[string]string
+	base    http.RoundTripper
+}
 
-	if host == "https://github.com" {
-		client = github.NewClient(httpClient)
-		return client, httpClient, nil
+// RoundTrip implements http.RoundTripper#RoundTrip
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
 	}
-	client, err = github.NewEnterpriseClient(host, "", httpClient)
-	return client, httpClient, err
+	return t.base.RoundTrip(req)
 }
 
-func newRepositoryHost(host string, client *github.Client, httpClient *http.Client, localMappings map[string]string, options manifest.ParsingOptions) repositoryhosts.RepositoryHost {
+func newRepositoryHost(host string, client *github.Client, httpClient *http.Client, localMappings map[string]string, options manifest.ParsingOptions, dateFormat string) repositoryhosts.RepositoryHost {
 	rawHost := "raw." + host
 	if host == "github.com" {
 		rawHost = "raw.githubusercontent.com"
 	}
-	return githubhttpcache.NewGHC(host, client, client.Repositories, client.Git, httpClient, &osshim.OsShim{}, []string{host, rawHost}, localMappings, options)
+	return githubhttpcache.NewGHC(host, client, client.Repositories, client.Git, httpClient, &osshim.OsShim{}, []string{host, rawHost}, localMappings, options, dateFormat)
 }
 
 // NewReactor creates a Reactor from Options
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("#buildClient", func() {
+	var (
+		server    *httptest.Server
+		requests  []*http.Request
+		cachePath string
+		options   repositoryhosts.RepositoryHostOptions
+		token     string
+	)
+	BeforeEach(func() {
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"default_branch": "master"}`))
+		}))
+		var err error
+		cachePath, err = os.MkdirTemp("", "docforge-cache")
+		Expect(err).NotTo(HaveOccurred())
+		options = repositoryhosts.RepositoryHostOptions{}
+		token = ""
+	})
+	AfterEach(func() {
+		server.Close()
+		Expect(os.RemoveAll(cachePath)).To(Succeed())
+	})
+	JustBeforeEach(func() {
+		client, _, err := buildClient(context.TODO(), token, server.URL, cachePath, options)
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = client.Repositories.Get(context.TODO(), "owner", "repo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+	})
+
+	It("uses the GitHub client defaults", func() {
+		Expect(requests[0].Header.Get("User-Agent")).To(Equal("go-github"))
+		Expect(requests[0].URL.Path).To(Equal("/api/v3/repos/owner/repo"))
+		Expect(requests[0].Header.Get("Authorization")).To(BeEmpty())
+	})
+
+	Context("access token is provided", func() {
+		BeforeEach(func() {
+			token = "secret"
+			options.Headers = map[string]string{"X-Trace-Id": "trace"}
+		})
+		It("authenticates the requests", func() {
+			Expect(requests[0].Header.Get("Authorization")).To(Equal("Bearer secret"))
+			Expect(requests[0].Header.Get("X-Trace-Id")).To(Equal("trace"))
+		})
+	})
+
+	Context("API base URL is configured", func() {
+		BeforeEach(func() {
+			host := strings.TrimPrefix(server.URL, "http://")
+			options.APIBaseURLs = map[string]string{host: server.URL + "/custom/api"}
+		})
+		It("targets the configured base URL", func() {
+			Expect(requests[0].URL.Path).To(Equal("/custom/api/repos/owner/repo"))
+		})
+	})
+
+	Context("user agent and headers are configured", func() {
+		BeforeEach(func() {
+			options.UserAgent = "docforge-test"
+			options.Headers = map[string]string{"X-Trace-Id": "trace"}
+		})
+		It("sets them on outgoing requests", func() {
+			Expect(requests[0].Header.Get("User-Agent")).To(Equal("docforge-test"))
+			Expect(requests[0].Header.Get("X-Trace-Id")).To(Equal("trace"))
+		})
+	})
+})
@@ -5,6 +5,8 @@
 package app
 
 import (
+	"time"
+
 	"github.com/gardener/docforge/cmd/hugo"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
 	"github.com/gardener/docforge/pkg/writers"
@@ -13,18 +15,25 @@ import (
 // Options encapsulates the parameters for creating
 // new Reactor objects
 type Options struct {
-	DocumentWorkersCount         int      `mapstructure:"document-workers"`
-	ValidationWorkersCount       int      `mapstructure:"validation-workers"`
-	FailFast                     bool     `mapstructure:"fail-fast"`
-	DestinationPath              string   `mapstructure:"destination"`
-	ResourcesPath                string   `mapstructure:"resources-download-path"`
-	ManifestPath                 string   `mapstructure:"manifest"`
-	ResourceDownloadWorkersCount int      `mapstructure:"download-workers"`
-	GhInfoDestination            string   `mapstructure:"github-info-destination"`
-	DryRun                       bool     `mapstructure:"dry-run"`
-	Resolve                      bool     `mapstructure:"resolve"`
-	ExtractedFilesFormats        []string `mapstructure:"extracted-files-formats"`
-	ValidateLinks                bool     `mapstructure:"validate-links"`
+	DocumentWorkersCount         int               `mapstructure:"document-workers"`
+	ValidationWorkersCount       int               `mapstructure:"validation-workers"`
+	FailFast                     bool              `mapstructure:"fail-fast"`
+	DestinationPath              string            `mapstructure:"destination"`
+	ResourcesPath                string            `mapstructure:"resources-download-path"`
+	ManifestPath                 string            `mapstructure:"manifest"`
+	ResourceDownloadWorkersCount int               `mapstructure:"download-workers"`
+	GhInfoDestination            string            `mapstructure:"github-info-destination"`
+	DryRun                       bool              `mapstructure:"dry-run"`
+	Resolve                      bool              `mapstructure:"resolve"`
+	ExtractedFilesFormats        []string          `mapstructure:"extracted-files-formats"`
+	ValidateLinks                bool              `mapstructure:"validate-links"`
+	ResumeDownloads              bool              `mapstructure:"resume-downloads"`
+	ValidationETagCache          string            `mapstructure:"validation-etag-cache"`
+	ValidatedLinksCache          string            `mapstructure:"validated-links-cache"`
+	ValidatedLinksTTL            time.Duration     `mapstructure:"validated-links-ttl"`
+	ManifestVars                 map[string]string `mapstructure:"manifest-vars"`
+	ManifestEnv                  bool              `mapstructure:"manifest-env"`
+	ManifestVarsStrict           bool              `mapstructure:"manifest-vars-strict"`
 }
 
 // Writers struct that collects all the writesr
@@ -7,3 +7,10 @@ package manifest
 func (n *Node) RemoveParent() {
 	n.parent = nil
 }
+
+func (n *Node) SetParents() {
+	for _, child := range n.Structure {
+		child.parent = n
+		child.SetParents()
+	}
+}
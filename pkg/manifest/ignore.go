@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	resourcehandlers "github.com/gardener/docforge/pkg/readers/repositoryhosts"
+)
+
+// IgnoreFileName is the name of the files in a fileTree that list gitignore-style
+// patterns of files excluded from the tree
+const IgnoreFileName = resourcehandlers.IgnoreFileName
+
+// ignoreRule is a single .docforgeignore pattern
+type ignoreRule struct {
+	pattern *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreRules are the rules of a .docforgeignore file, the last matching rule wins
+type ignoreRules []ignoreRule
+
+// ignoreTree holds the rules of the .docforgeignore files of a fileTree by the slash-delimited
+// path of their directory relative to the tree root, "." for the root
+type ignoreTree map[string]ignoreRules
+
+// parseIgnoreRules parses gitignore-style patterns supporting comments, negation with '!',
+// directory only patterns ending with '/', patterns anchored to the directory of the
+// .docforgeignore file and '*', '?' and '**' wildcards
+func parseIgnoreRules(content string) (ignoreRules, error) {
+	var rules ignoreRules
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := ignoreRule{}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		expr := globToRegexp(line)
+		if !anchored {
+			expr = "(?:.*/)?" + expr
+		}
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %s: %w", IgnoreFileName, line, err)
+		}
+		rule.pattern = re
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// match returns if a rule matches p and if the last matching rule excludes it
+func (r ignoreRules) match(p string, isDir bool) (matched bool, ignored bool) {
+	for _, rule := range r {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.pattern.MatchString(p) {
+			matched, ignored = true, !rule.negate
+		}
+	}
+	return matched, ignored
+}
+
+// ignored returns true if the last rule matching p excludes it. The rules of the .docforgeignore
+// files in the parent directories of p are matched with the path relative to their directory,
+// the rules of deeper files take precedence.
+func (t ignoreTree) ignored(p string, isDir bool) bool {
+	var dirs []string
+	for dir := range t {
+		if dir == "." || strings.HasPrefix(p, dir+"/") {
+			dirs = append(dirs, dir)
+		}
+	}
+	// the parent directories are prefixes of each other, the shorter the higher in the tree
+	sort.Slice(dirs, func(i, j int) bool {
+		return dirs[i] == "." || dirs[j] != "." && len(dirs[i]) < len(dirs[j])
+	})
+	ignored := false
+	for _, dir := range dirs {
+		rel := p
+		if dir != "." {
+			rel = strings.TrimPrefix(p, dir+"/")
+		}
+		if matched, ign := t[dir].match(rel, isDir); matched {
+			ignored = ign
+		}
+	}
+	return ignored
+}
+
+// excludes returns true if file or any of its parent directories is ignored.
+// As in git, a file can't be re-included if its parent directory is ignored.
+func (t ignoreTree) excludes(file string) bool {
+	if len(t) == 0 {
+		return false
+	}
+	dir := path.Dir(file)
+	if dir != "." {
+		segments := strings.Split(dir, "/")
+		for i := range segments {
+			if t.ignored(strings.Join(segments[:i+1], "/"), true) {
+				return true
+			}
+		}
+	}
+	return t.ignored(file, false)
+}
+
+// loadIgnoreRules reads the .docforgeignore files listed in the files of a fileTree
+// and returns their rules together with the files that are not .docforgeignore files
+func loadIgnoreRules(fs resourcehandlers.RepositoryHost, fileTree string, files []string) (ignoreTree, []string, error) {
+	tree := ignoreTree{}
+	var rest []string
+	for _, file := range files {
+		if path.Base(file) != IgnoreFileName {
+			rest = append(rest, file)
+			continue
+		}
+		ignoreFile, err := resourcehandlers.TreeFileURL(fileTree, file)
+		if err != nil {
+			return nil, nil, err
+		}
+		content, err := fs.Read(context.TODO(), ignoreFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't read %s : %w", ignoreFile, err)
+		}
+		if tree[path.Dir(file)], err = parseIgnoreRules(string(content)); err != nil {
+			return nil, nil, err
+		}
+	}
+	return tree, rest, nil
+}
@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// variableRef matches `$$`, `${VAR}` and `$VAR`
+var variableRef = regexp.MustCompile(`\$(?:\$|\{([A-Za-z_][A-Za-z0-9_]*)\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// manifestVariableRef matches `{{VAR}}` references to the manifest variables, `{{.key}}` name templates don't match
+var manifestVariableRef = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Interpolation defines how variable references in manifests are substituted
+type Interpolation struct {
+	// Vars are the variable values, the process environment is used if nil
+	Vars map[string]string
+	// Strict fails on references to undefined variables, otherwise they are kept as is
+	Strict bool
+}
+
+// interpolate substitutes `${VAR}` and `$VAR` references in content. `$$` produces a literal `$`.
+func (i Interpolation) interpolate(content string) (string, error) {
+	vars := i.variables()
+	var undefined []string
+	out := variableRef.ReplaceAllStringFunc(content, func(ref string) string {
+		if ref == "$$" {
+			return "$"
+		}
+		m := variableRef.FindStringSubmatch(ref)
+		name := m[1] + m[2]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		undefined = append(undefined, name)
+		return ref
+	})
+	if i.Strict && len(undefined) > 0 {
+		return "", fmt.Errorf("undefined variables: %s", strings.Join(undefined, ", "))
+	}
+	return out, nil
+}
+
+// variables returns Vars or the process environment if Vars is nil
+func (i Interpolation) variables() map[string]string {
+	if i.Vars != nil {
+		return i.Vars
+	}
+	vars := map[string]string{}
+	for _, env := range os.Environ() {
+		if k, v, ok := strings.Cut(env, "="); ok {
+			vars[k] = v
+		}
+	}
+	return vars
+}
+
+// inheritVariables returns the variables declared by a manifest merged with the variables inherited
+// from the including manifest, the declared ones take precedence
+func inheritVariables(declared map[string]string, inherited map[string]string) map[string]string {
+	if len(inherited) == 0 {
+		return declared
+	}
+	vars := maps.Clone(inherited)
+	maps.Copy(vars, declared)
+	return vars
+}
+
+// withManifestVariables returns the interpolation of the `{{VAR}}` references of a manifest with its
+// variables vars. The variables are defaults, the values of interpolation take precedence. If interpolation
+// is nil only vars are substituted.
+func withManifestVariables(interpolation *Interpolation, vars map[string]string) *Interpolation {
+	if len(vars) == 0 {
+		return interpolation
+	}
+	out := &Interpolation{Vars: maps.Clone(vars)}
+	if interpolation != nil {
+		for k, v := range interpolation.variables() {
+			out.Vars[k] = v
+		}
+		out.Strict = interpolation.Strict
+	}
+	return out
+}
+
+// substituteVariables substitutes the `{{VAR}}` references to the variables of interpolation in the sources,
+// names and properties of the nodes of a parsed manifest. References to undefined variables are kept as they
+// are, unless interpolation is strict.
+func substituteVariables(manifest *Node, interpolation *Interpolation) error {
+	if interpolation == nil {
+		return nil
+	}
+	s := &substitution{vars: interpolation.variables()}
+	s.properties(manifest.DefaultProperties)
+	for _, node := range manifest.Structure {
+		s.node(node)
+	}
+	if interpolation.Strict && len(s.undefined) > 0 {
+		return fmt.Errorf("undefined variables: %s", strings.Join(s.undefined, ", "))
+	}
+	return nil
+}
+
+// substitution substitutes `{{VAR}}` references collecting the undefined variables
+type substitution struct {
+	vars      map[string]string
+	undefined []string
+}
+
+func (s *substitution) node(node *Node) {
+	for _, field := range []*string{&node.Manifest, &node.File, &node.Source, &node.Dir, &node.FileTree} {
+		*field = s.string(*field)
+	}
+	for i := range node.MultiSource {
+		node.MultiSource[i] = s.string(node.MultiSource[i])
+	}
+	s.properties(node.Properties)
+	s.properties(node.Frontmatter)
+	s.properties(node.DefaultProperties)
+	for _, child := range node.Structure {
+		s.node(child)
+	}
+}
+
+func (s *substitution) properties(properties map[string]interface{}) {
+	for k, v := range properties {
+		properties[k] = s.value(v)
+	}
+}
+
+func (s *substitution) value(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return s.string(v)
+	case map[string]interface{}:
+		s.properties(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = s.value(v[i])
+		}
+	}
+	return value
+}
+
+func (s *substitution) string(str string) string {
+	return manifestVariableRef.ReplaceAllStringFunc(str, func(ref string) string {
+		name := manifestVariableRef.FindStringSubmatch(ref)[1]
+		if v, ok := s.vars[name]; ok {
+			return v
+		}
+		if !slices.Contains(s.undefined, name) {
+			s.undefined = append(s.undefined, name)
+		}
+		return ref
+	})
+}
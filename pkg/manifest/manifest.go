@@ -5,11 +5,17 @@
 package manifest
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"net/url"
+	"io"
 	"path"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"text/template"
 
 	resourcehandlers "github.com/gardener/docforge/pkg/readers/repositoryhosts"
 	"gopkg.in/yaml.v2"
@@ -38,7 +44,16 @@ func processManifest(f nodeTransformation, node *Node, parent *Node, manifest *N
 	return nil
 }
 
-func loadManifestStructure(node *Node, parent *Node, manifest *Node, r resourcehandlers.Registry) error {
+// loadManifestStructure loads manifests substituting the `${VAR}` and `$VAR` references in their
+// content before it's parsed, if interpolation is not nil, and the `{{VAR}}` references to their
+// variables in the parsed nodes
+func loadManifestStructure(interpolation *Interpolation) nodeTransformation {
+	return func(node *Node, parent *Node, manifest *Node, r resourcehandlers.Registry) error {
+		return loadManifest(node, manifest, r, interpolation)
+	}
+}
+
+func loadManifest(node *Node, manifest *Node, r resourcehandlers.Registry, interpolation *Interpolation) error {
 	if node.Manifest == "" {
 		return nil
 	}
@@ -60,8 +75,55 @@ func loadManifestStructure(node *Node, parent *Node, manifest *Node, r resourceh
 		return fmt.Errorf("can't get manifest file content : %w", err)
 	}
 	content := string(byteContent)
+	if interpolation != nil {
+		if content, err = interpolation.interpolate(content); err != nil {
+			return fmt.Errorf("can't interpolate manifest %s : %w", node.Manifest, err)
+		}
+	}
 	if err = yaml.Unmarshal([]byte(content), node); err != nil {
-		return fmt.Errorf("can't parse manifest %s yaml content : %w", node.Manifest, err)
+		return fmt.Errorf("can't parse manifest %s yaml content : %w", node.Manifest, newParseError(node.Manifest, content, err))
+	}
+	// included manifests inherit the variables of the including one
+	if manifest != node {
+		node.Variables = inheritVariables(node.Variables, manifest.Variables)
+	}
+	if err = substituteVariables(node, withManifestVariables(interpolation, node.Variables)); err != nil {
+		return fmt.Errorf("can't substitute the variables of manifest %s : %w", node.Manifest, err)
+	}
+	return nil
+}
+
+// applyDefaultProperties merges the default properties of the nearest manifest into the document properties
+func applyDefaultProperties(node *Node, _ *Node, manifest *Node, _ resourcehandlers.Registry) error {
+	switch {
+	case node.Type == "manifest" && node != manifest:
+		node.DefaultProperties = mergeMissingKeys(node.DefaultProperties, manifest.DefaultProperties)
+	case node.IsDocument():
+		node.MergeProperties(manifest.DefaultProperties, false)
+	}
+	return nil
+}
+
+// renderNameTemplates renders the names of file and dir nodes containing template actions
+// as Go templates with the node properties, e.g. `{{.version}} Release Notes`
+func renderNameTemplates(node *Node, _ *Node, _ *Node, _ resourcehandlers.Registry) error {
+	name := node.Name()
+	if !strings.Contains(name, "{{") {
+		return nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(name)
+	if err != nil {
+		return fmt.Errorf("can't parse the name template of node %s : %w", node.NodePath(), err)
+	}
+	buf := bytes.Buffer{}
+	if err = tmpl.Execute(&buf, node.Properties); err != nil {
+		return fmt.Errorf("can't render the name of node %s : %w", node.NodePath(), err)
+	}
+	switch node.Type {
+	case "file":
+		node.File = buf.String()
+	case "dir":
+		node.Dir = buf.String()
 	}
 	return nil
 }
@@ -157,27 +219,94 @@ func resolveRelativeLinks(node *Node, _ *Node, manifest *Node, r resourcehandler
 	return nil
 }
 
-func extractFilesFromNode(node *Node, parent *Node, manifest *Node, r resourcehandlers.Registry) error {
-	switch node.Type {
-	case "file":
-		if !strings.HasSuffix(node.File, ".md") {
-			node.File += ".md"
+// fileTreeWorkers is the number of file trees resolved concurrently
+const fileTreeWorkers = 8
+
+// extractFilesFromNodes resolves the file trees of the fileTree nodes concurrently
+// and expands the nodes into the resolved files
+func extractFilesFromNodes(manifest *Node, r resourcehandlers.Registry) error {
+	var fileTrees []*Node
+	collect := func(node *Node, _ *Node, _ *Node, _ resourcehandlers.Registry) error {
+		if node.Type == "fileTree" {
+			fileTrees = append(fileTrees, node)
 		}
-	case "fileTree":
-		fs, err := r.Get(node.FileTree)
-		if err != nil {
-			return err
+		return nil
+	}
+	if err := processManifest(collect, manifest, nil, manifest, r); err != nil {
+		return err
+	}
+	files, err := ResolveFileTrees(fileTrees, r, fileTreeWorkers)
+	if err != nil {
+		return err
+	}
+	trees := make(map[*Node][]string, len(fileTrees))
+	for i, node := range fileTrees {
+		trees[node] = files[i]
+	}
+	return processManifest(extractFilesFromNode(trees), manifest, nil, manifest, r)
+}
+
+func extractFilesFromNode(trees map[*Node][]string) nodeTransformation {
+	return func(node *Node, parent *Node, _ *Node, _ resourcehandlers.Registry) error {
+		switch node.Type {
+		case "file":
+			if !strings.HasSuffix(node.File, ".md") {
+				node.File += ".md"
+			}
+		case "fileTree":
+			if err := constructNodeTree(trees[node], node, parent); err != nil {
+				return err
+			}
+			removeNodeFromParent(node, parent)
 		}
-		files, err := fs.Tree(node.FileTree)
+		return nil
+	}
+}
+
+// ResolveFileTrees resolves the files of the fileTree nodes using up to workers concurrent walks and
+// removes the files excluded by .docforgeignore rules. The files are returned in the order of the nodes
+// regardless of the completion order and the error of the first failed node is returned.
+func ResolveFileTrees(nodes []*Node, r resourcehandlers.Registry, workers int) ([][]string, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	files := make([][]string, len(nodes))
+	errs := make([]error, len(nodes))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, node *Node) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			files[i], errs[i] = resolveFileTree(node, r)
+		}(i, node)
+	}
+	wg.Wait()
+	for _, err := range errs {
 		if err != nil {
-			return err
-		}
-		if err := constructNodeTree(files, node, parent); err != nil {
-			return err
+			return nil, err
 		}
-		removeNodeFromParent(node, parent)
 	}
-	return nil
+	return files, nil
+}
+
+// resolveFileTree returns the files of a fileTree node that are not ignored
+func resolveFileTree(node *Node, r resourcehandlers.Registry) ([]string, error) {
+	fs, err := r.Get(node.FileTree)
+	if err != nil {
+		return nil, err
+	}
+	files, err := fs.Tree(node.FileTree)
+	if err != nil {
+		return nil, err
+	}
+	rules, files, err := loadIgnoreRules(fs, node.FileTree, files)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(files, rules.excludes), nil
 }
 
 func removeNodeFromParent(node *Node, parent *Node) {
@@ -192,11 +321,17 @@ func removeNodeFromParent(node *Node, parent *Node) {
 }
 
 func constructNodeTree(files []string, node *Node, parent *Node) error {
+	includePattern, err := node.compileIncludePattern()
+	if err != nil {
+		return err
+	}
 	pathToDirNode := map[string]*Node{}
 	pathToDirNode[node.Path] = parent
 	for _, file := range files {
-		extension := path.Ext(file)
-		if extension != ".md" && extension != "" {
+		if !node.includesExtension(path.Ext(file)) || node.excludesName(file) {
+			continue
+		}
+		if includePattern != nil && !includePattern.MatchString(file) {
 			continue
 		}
 		shouldExclude := false
@@ -209,17 +344,13 @@ func constructNodeTree(files []string, node *Node, parent *Node) error {
 		if shouldExclude {
 			continue
 		}
-		source, err := url.JoinPath(strings.Replace(node.FileTree, "/tree/", "/blob/", 1), file)
-		if err != nil {
-			return err
-		}
-		// url.JoinPath escapes once so we revert it's escape
-		source, err = url.PathUnescape(source)
+		source, err := resourcehandlers.TreeFileURL(node.FileTree, file)
 		if err != nil {
 			return err
 		}
+		// the files without extension are markdown documents, the others keep their extension
 		fileName := path.Base(file)
-		if !strings.HasSuffix(fileName, ".md") {
+		if path.Ext(fileName) == "" {
 			fileName = fileName + ".md"
 		}
 		filePath := path.Join(node.Path, path.Dir(file))
@@ -236,6 +367,44 @@ func constructNodeTree(files []string, node *Node, parent *Node) error {
 	return nil
 }
 
+// excludesName checks if a file is excluded by the fileTree ExcludeNames either directly or
+// by one of its parent directories
+func (n *Node) excludesName(file string) bool {
+	for _, p := range n.ExcludeNames {
+		p = strings.Trim(p, "/")
+		if file == p || strings.HasPrefix(file, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// compileIncludePattern compiles the fileTree IncludePattern, it returns nil if there is none
+func (n *Node) compileIncludePattern() (*regexp.Regexp, error) {
+	if n.IncludePattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(n.IncludePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid includePattern of fileTree %s : %w", n.FileTree, err)
+	}
+	return re, nil
+}
+
+// includesExtension checks if files with extension are included by the fileTree IncludeExtensions,
+// only markdown and extensionless files are included if there are none
+func (n *Node) includesExtension(extension string) bool {
+	if len(n.IncludeExtensions) == 0 {
+		return extension == ".md" || extension == ""
+	}
+	for _, e := range n.IncludeExtensions {
+		if strings.EqualFold(strings.TrimPrefix(e, "."), strings.TrimPrefix(extension, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
 func getParrentNode(pathToDirNode map[string]*Node, parentPath string) *Node {
 	if parent, ok := pathToDirNode[parentPath]; ok {
 		return parent
@@ -354,12 +523,42 @@ func calculateAliases(node *Node, parent *Node, _ *Node, _ resourcehandlers.Regi
 
 // ResolveManifest collects files in FileCollector from a given url and resourcehandlers.FileSource
 func ResolveManifest(url string, r resourcehandlers.Registry) ([]*Node, error) {
+	return resolveManifest(url, r, loadManifestStructure(nil))
+}
+
+// ResolveManifestWithVars resolves a manifest like ResolveManifest substituting `${VAR}` and `$VAR`
+// references in the content of the manifest and the manifests it includes before they are parsed
+func ResolveManifestWithVars(url string, r resourcehandlers.Registry, interpolation Interpolation) ([]*Node, error) {
+	return resolveManifest(url, r, loadManifestStructure(&interpolation))
+}
+
+// ParseMulti parses the manifest documents of a YAML stream separated by `---` in order, skipping
+// empty documents. The documents are parsed as they are, without resolving their content.
+func ParseMulti(blob []byte) ([]*Node, error) {
+	var manifests []*Node
+	decoder := yaml.NewDecoder(bytes.NewReader(blob))
+	for i := 1; ; i++ {
+		node := &Node{}
+		err := decoder.Decode(node)
+		if errors.Is(err, io.EOF) {
+			return manifests, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't parse manifest document %d yaml content : %w", i, err)
+		}
+		if !node.Equal(&Node{}) {
+			manifests = append(manifests, node)
+		}
+	}
+}
+
+func resolveManifest(url string, r resourcehandlers.Registry, load nodeTransformation) ([]*Node, error) {
 	manifest := Node{
 		ManifType: ManifType{
 			Manifest: url,
 		},
 	}
-	if err := processManifest(loadManifestStructure, &manifest, nil, &manifest, r); err != nil {
+	if err := processManifest(load, &manifest, nil, &manifest, r); err != nil {
 		return nil, err
 	}
 	if err := processManifest(decideNodeType, &manifest, nil, &manifest, r); err != nil {
@@ -371,7 +570,16 @@ func ResolveManifest(url string, r resourcehandlers.Registry) ([]*Node, error) {
 	if err := processManifest(resolveRelativeLinks, &manifest, nil, &manifest, r); err != nil {
 		return nil, err
 	}
-	if err := processManifest(extractFilesFromNode, &manifest, nil, &manifest, r); err != nil {
+	if err := extractFilesFromNodes(&manifest, r); err != nil {
+		return nil, err
+	}
+	if err := processManifest(applyDefaultProperties, &manifest, nil, &manifest, r); err != nil {
+		return nil, err
+	}
+	if err := processManifest(renderNameTemplates, &manifest, nil, &manifest, r); err != nil {
+		return nil, err
+	}
+	if err := processManifest(calculatePath, &manifest, nil, &manifest, r); err != nil {
 		return nil, err
 	}
 	if err := processManifest(moveManifestContentIntoTree, &manifest, nil, &manifest, r); err != nil {
@@ -9,12 +9,18 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	_ "embed"
 
 	"github.com/gardener/docforge/pkg/manifest"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/filesystem"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts/repositoryhostsfakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -46,6 +52,15 @@ var _ = Describe("Manifest test", func() {
 
 				fakeFiles := &repositoryhostsfakes.FakeRepositoryHost{}
 				fakeFiles.ReadCalls(func(ctx context.Context, url string) ([]byte, error) {
+					if strings.HasSuffix(url, manifest.IgnoreFileName) {
+						ignoreFiles := map[string]string{}
+						ignoreFiles["https://test/ignored/.docforgeignore"] = "# comment\n*.tmp.md\n/drafts/\nprivate\n!private/public.md\nlegacy/**/old.md\n"
+						ignoreFiles["https://test/ignored/sub/.docforgeignore"] = "!keep.tmp.md\n/local.md\n"
+						if content, ok := ignoreFiles[url]; ok {
+							return []byte(content), nil
+						}
+						return nil, repositoryhosts.ErrResourceNotFound(url)
+					}
 					return examples.ReadFile(strings.TrimPrefix(url, "https://test"))
 				})
 				fakeFiles.ToAbsLinkCalls(func(url, link string) (string, error) {
@@ -58,6 +73,9 @@ var _ = Describe("Manifest test", func() {
 					files := map[string][]string{}
 					files["https://test/website"] = []string{"blog/2023/_index.md"}
 					files["https://test/blogs"] = []string{"2023/one", "2023/two.md"}
+					files["https://test/mixed"] = []string{"README.md", "LICENSE", "img/logo.png", "guides/setup.md", "guides/notes", "guides/intro.markdown"}
+					files["https://test/excluded"] = []string{"a.md", "guides/setup.md", "guides/old/one.md", "guides/old/two.md", "api/ref.md"}
+					files["https://test/ignored"] = []string{".docforgeignore", "keep.md", "notes.tmp.md", "drafts/draft.md", "sub/.docforgeignore", "sub/drafts/nested.md", "sub/keep.tmp.md", "sub/local.md", "local.md", "private/secret.md", "private/public.md", "legacy/a/b/old.md", "legacy/new.md"}
 					if res, ok := files[url]; !ok {
 						return nil, errors.New("err")
 					} else {
@@ -88,6 +106,375 @@ var _ = Describe("Manifest test", func() {
 			Entry("covering _index.md use cases", "_index_md_with_properties"),
 			Entry("covering fileTree use cases and dir merges", "filetree"),
 			Entry("covering manifest use cases", "manifest"),
+			Entry("covering .docforgeignore use cases", "docforgeignore"),
+			Entry("covering fileTree includeExtensions use cases", "include_extensions"),
+			Entry("covering fileTree includeExtensions of non-markdown extensions", "include_extensions_markdown"),
+			Entry("covering fileTree excludeNames use cases", "exclude_names"),
+		)
+	})
+	Describe("Parsing malformed manifests", func() {
+		DescribeTable("reports the error position",
+			func(example string, line int, snippet string) {
+				_, err := manifest.ResolveManifest(fmt.Sprintf("tests/examples/%s.yaml", example), repositoryhostsfakes.FilesystemRegistry(examples))
+				var parseErr *manifest.ParseError
+				Expect(errors.As(err, &parseErr)).To(BeTrue())
+				Expect(parseErr.Line).To(Equal(line))
+				Expect(parseErr.Snippet).To(ContainSubstring(snippet))
+				Expect(errors.Unwrap(parseErr)).To(MatchError(ContainSubstring("yaml:")))
+			},
+			Entry("syntax error", "invalid_syntax", 5, ">    5 |   source: b: c"),
+			Entry("type error", "invalid_types", 4, ">    4 |   - file: [a.md]"),
+		)
+	})
+	Describe("Resolving file trees concurrently", func() {
+		var (
+			nodes       []*manifest.Node
+			registry    *repositoryhostsfakes.FakeRegistry
+			running     int32
+			maxRunning  int32
+			treeContent map[string][]string
+			fakeFiles   *repositoryhostsfakes.FakeRepositoryHost
+		)
+		BeforeEach(func() {
+			running, maxRunning = 0, 0
+			treeContent = map[string][]string{}
+			nodes = nil
+			for i := 0; i < 6; i++ {
+				tree := fmt.Sprintf("https://test/tree%d", i)
+				treeContent[tree] = []string{fmt.Sprintf("doc%d.md", i), "drafts/draft.md"}
+				if i == 2 {
+					treeContent[tree] = append(treeContent[tree], ".docforgeignore")
+				}
+				nodes = append(nodes, &manifest.Node{Type: "fileTree", FilesTreeType: manifest.FilesTreeType{FileTree: tree}})
+			}
+			fakeFiles = &repositoryhostsfakes.FakeRepositoryHost{}
+			fakeFiles.TreeCalls(func(url string) ([]string, error) {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				// later trees complete first
+				var i int
+				_, _ = fmt.Sscanf(url, "https://test/tree%d", &i)
+				time.Sleep(time.Duration(6-i) * 5 * time.Millisecond)
+				if files, ok := treeContent[url]; ok {
+					return append([]string{}, files...), nil
+				}
+				return nil, errors.New("no tree " + url)
+			})
+			fakeFiles.ReadCalls(func(ctx context.Context, url string) ([]byte, error) {
+				if url == "https://test/tree2/.docforgeignore" {
+					return []byte("/drafts/\n"), nil
+				}
+				return nil, repositoryhosts.ErrResourceNotFound(url)
+			})
+			registry = &repositoryhostsfakes.FakeRegistry{}
+			registry.GetReturns(fakeFiles, nil)
+		})
+		It("returns the same files as a sequential run in the order of the nodes", func() {
+			sequential, err := manifest.ResolveFileTrees(nodes, registry, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(maxRunning).To(Equal(int32(1)))
+			maxRunning = 0
+			concurrent, err := manifest.ResolveFileTrees(nodes, registry, 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(maxRunning).To(BeNumerically(">", 1))
+			Expect(maxRunning).To(BeNumerically("<=", 3))
+			Expect(concurrent).To(Equal(sequential))
+			Expect(concurrent).To(HaveLen(6))
+			Expect(concurrent[0]).To(Equal([]string{"doc0.md", "drafts/draft.md"}))
+			Expect(concurrent[2]).To(Equal([]string{"doc2.md"}))
+			// only the listed ignore files are read
+			for i := 0; i < fakeFiles.ReadCallCount(); i++ {
+				_, url := fakeFiles.ReadArgsForCall(i)
+				Expect(url).To(Equal("https://test/tree2/.docforgeignore"))
+			}
+		})
+		It("returns the error of the first failed node", func() {
+			nodes[4].FileTree = "https://test/missing4"
+			nodes[1].FileTree = "https://test/missing1"
+			_, err := manifest.ResolveFileTrees(nodes, registry, 3)
+			Expect(err).To(MatchError("no tree https://test/missing1"))
+		})
+	})
+	Describe("Resolving manifests through the registry", func() {
+		var (
+			dir      string
+			registry repositoryhosts.Registry
+		)
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "manifest")
+			Expect(err).NotTo(HaveOccurred())
+			github := &repositoryhostsfakes.FakeRepositoryHost{}
+			github.AcceptCalls(func(link string) bool {
+				return strings.HasPrefix(link, "https://github.com/")
+			})
+			github.ToAbsLinkCalls(func(source, link string) (string, error) {
+				if strings.HasPrefix(link, "https://") {
+					return link, nil
+				}
+				return source[:strings.LastIndex(source, "/")+1] + strings.TrimPrefix(link, "./"), nil
+			})
+			github.ReadCalls(func(ctx context.Context, url string) ([]byte, error) {
+				if url == "https://github.com/gardener/docs/blob/master/manifest.yaml" {
+					return []byte("structure:\n- dir: remote\n  structure:\n  - file: two.md\n    source: ./two.md\n"), nil
+				}
+				return nil, repositoryhosts.ErrResourceNotFound(url)
+			})
+			registry = repositoryhosts.NewRegistry(github, filesystem.New())
+		})
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+		It("resolves a manifest from a GitHub source", func() {
+			nodes, err := manifest.ResolveManifest("https://github.com/gardener/docs/blob/master/manifest.yaml", registry)
+			Expect(err).NotTo(HaveOccurred())
+			sources := map[string]string{}
+			for _, node := range nodes {
+				if node.IsDocument() {
+					sources[node.NodePath()] = node.Source
+				}
+			}
+			Expect(sources).To(Equal(map[string]string{
+				"remote/two.md": "https://github.com/gardener/docs/blob/master/two.md",
+			}))
+		})
+		It("resolves a manifest from a local file including a GitHub manifest", func() {
+			content := "structure:\n- file: one.md\n  source: ./docs/one.md\n- manifest: https://github.com/gardener/docs/blob/master/manifest.yaml\n"
+			manifestPath := filepath.Join(dir, "manifest.yaml")
+			Expect(os.WriteFile(manifestPath, []byte(content), 0644)).To(Succeed())
+			nodes, err := manifest.ResolveManifest(manifestPath, registry)
+			Expect(err).NotTo(HaveOccurred())
+			sources := map[string]string{}
+			for _, node := range nodes {
+				if node.IsDocument() {
+					sources[node.NodePath()] = node.Source
+				}
+			}
+			Expect(sources).To(Equal(map[string]string{
+				"one.md":        filepath.ToSlash(filepath.Join(dir, "docs", "one.md")),
+				"remote/two.md": "https://github.com/gardener/docs/blob/master/two.md",
+			}))
+		})
+		Describe("fileTree includePattern", func() {
+			var manifestPath string
+			BeforeEach(func() {
+				for _, f := range []string{"a.md", "guides/setup.md", "guides/old/one.md", "api/ref.md", "api/guides.md"} {
+					fn := filepath.Join(dir, "docs", filepath.FromSlash(f))
+					Expect(os.MkdirAll(filepath.Dir(fn), os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(fn, []byte(f), 0644)).To(Succeed())
+				}
+				manifestPath = filepath.Join(dir, "manifest.yaml")
+			})
+			It("includes only the files matching the pattern", func() {
+				content := "structure:\n- fileTree: ./docs\n  includePattern: ^guides/.*\\.md$\n"
+				Expect(os.WriteFile(manifestPath, []byte(content), 0644)).To(Succeed())
+				nodes, err := manifest.ResolveManifest(manifestPath, registry)
+				Expect(err).NotTo(HaveOccurred())
+				paths := []string{}
+				for _, node := range nodes {
+					if node.IsDocument() {
+						paths = append(paths, node.NodePath())
+					}
+				}
+				Expect(paths).To(ConsistOf("guides/setup.md", "guides/old/one.md"))
+			})
+			It("reports invalid patterns", func() {
+				content := "structure:\n- fileTree: ./docs\n  includePattern: guides/(\n"
+				Expect(os.WriteFile(manifestPath, []byte(content), 0644)).To(Succeed())
+				_, err := manifest.ResolveManifest(manifestPath, registry)
+				Expect(err).To(MatchError(ContainSubstring("invalid includePattern of fileTree")))
+			})
+		})
+		It("reports local manifests that don't exist", func() {
+			_, err := manifest.ResolveManifest(filepath.Join(dir, "missing.yaml"), registry)
+			var notFound repositoryhosts.ErrResourceNotFound
+			Expect(errors.As(err, &notFound)).To(BeTrue())
+		})
+	})
+	Describe("Parsing multi-document manifests", func() {
+		It("returns the non-empty documents in order", func() {
+			blob := []byte("structure:\n- file: one.md\n  source: https://a/one.md\n---\n---\n# only a comment\n---\nstructure:\n- dir: docs\n  structure:\n  - file: two.md\n    source: https://a/two.md\n")
+			manifests, err := manifest.ParseMulti(blob)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifests).To(HaveLen(2))
+			Expect(manifests[0].Structure).To(HaveLen(1))
+			Expect(manifests[0].Structure[0].File).To(Equal("one.md"))
+			Expect(manifests[0].Structure[0].Source).To(Equal("https://a/one.md"))
+			Expect(manifests[1].Structure).To(HaveLen(1))
+			Expect(manifests[1].Structure[0].Dir).To(Equal("docs"))
+			Expect(manifests[1].Structure[0].Structure[0].File).To(Equal("two.md"))
+		})
+		It("returns no documents for an empty stream", func() {
+			manifests, err := manifest.ParseMulti([]byte("---\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifests).To(BeEmpty())
+		})
+		It("reports the malformed document", func() {
+			_, err := manifest.ParseMulti([]byte("structure: []\n---\nstructure: [a: b: c]\n"))
+			Expect(err).To(MatchError(ContainSubstring("manifest document 2")))
+		})
+	})
+	Describe("Resolving manifests with default properties", func() {
+		It("applies them to the documents without the properties", func() {
+			nodes, err := manifest.ResolveManifest("tests/examples/default_properties.yaml", repositoryhostsfakes.FilesystemRegistry(examples))
+			Expect(err).NotTo(HaveOccurred())
+			properties := map[string]map[string]interface{}{}
+			for _, node := range nodes {
+				if node.IsDocument() {
+					properties[node.NodePath()] = node.Properties
+				}
+			}
+			Expect(properties).To(Equal(map[string]map[string]interface{}{
+				"docs/plain.md":  {"layout": "doc", "weight": 1},
+				"docs/custom.md": {"layout": "custom", "weight": 1},
+				"nested.md":      {"layout": "doc", "weight": 2},
+			}))
+		})
+	})
+	Describe("Resolving manifests with name templates", func() {
+		It("renders the names with the node properties", func() {
+			nodes, err := manifest.ResolveManifest("tests/examples/name_templates.yaml", repositoryhostsfakes.FilesystemRegistry(examples))
+			Expect(err).NotTo(HaveOccurred())
+			var paths []string
+			for _, node := range nodes {
+				if node.IsDocument() {
+					paths = append(paths, node.NodePath())
+				}
+			}
+			Expect(paths).To(ConsistOf("stable releases/v1.2 Release Notes.md", "stable releases/docforge overview.md", "plain.md"))
+		})
+		It("reports the node of a name that can't be rendered", func() {
+			_, err := manifest.ResolveManifest("tests/examples/name_templates_invalid.yaml", repositoryhostsfakes.FilesystemRegistry(examples))
+			Expect(err).To(MatchError(ContainSubstring("can't render the name of node releases/{{.version}} Release Notes.md")))
+		})
+	})
+	Describe("Resolving manifests with variables", func() {
+		var (
+			interpolation manifest.Interpolation
+			nodes         []*manifest.Node
+			err           error
 		)
+		BeforeEach(func() {
+			interpolation = manifest.Interpolation{Vars: map[string]string{"ORG": "gardener", "VERSION": "v1"}}
+		})
+		JustBeforeEach(func() {
+			nodes, err = manifest.ResolveManifestWithVars("tests/examples/variables.yaml", repositoryhostsfakes.FilesystemRegistry(examples), interpolation)
+		})
+		It("substitutes defined variables", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodes).To(HaveLen(3))
+			Expect(nodes[1].Dir).To(Equal("gardener"))
+			Expect(nodes[1].Frontmatter["title"]).To(Equal("v1 costs $5"))
+			Expect(nodes[2].Source).To(Equal("/website/v1/readme.md"))
+		})
+		Context("undefined variables", func() {
+			BeforeEach(func() {
+				interpolation.Vars = map[string]string{"VERSION": "v1"}
+			})
+			It("keeps the references", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes[1].Dir).To(Equal("${ORG}"))
+			})
+			Context("strict", func() {
+				BeforeEach(func() {
+					interpolation.Strict = true
+				})
+				It("fails", func() {
+					Expect(err).To(MatchError(ContainSubstring("undefined variables: ORG")))
+				})
+			})
+		})
+		Context("manifest declares variables", func() {
+			var manifestURL string
+			BeforeEach(func() {
+				manifestURL = "tests/examples/manifest_variables.yaml"
+				interpolation.Vars = map[string]string{"VERSION": "v2"}
+			})
+			JustBeforeEach(func() {
+				nodes, err = manifest.ResolveManifestWithVars(manifestURL, repositoryhostsfakes.FilesystemRegistry(examples), interpolation)
+			})
+			It("substitutes them unless they are provided", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes).To(HaveLen(3))
+				Expect(nodes[1].Dir).To(Equal("gardener"))
+				Expect(nodes[1].Properties["version"]).To(Equal("v2"))
+				Expect(nodes[2].File).To(Equal("guide-v2.md"))
+				Expect(nodes[2].Source).To(Equal("/website/v2/readme.md"))
+			})
+			It("substitutes them without interpolation", func() {
+				nodes, err = manifest.ResolveManifest(manifestURL, repositoryhostsfakes.FilesystemRegistry(examples))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes[0].Variables).To(Equal(map[string]string{"ORG": "gardener", "VERSION": "v1"}))
+				Expect(nodes[2].File).To(Equal("guide-v1.md"))
+				Expect(nodes[2].Source).To(Equal("/website/v1/readme.md"))
+			})
+			It("keeps the other $ text", func() {
+				nodes, err = manifest.ResolveManifest(manifestURL, repositoryhostsfakes.FilesystemRegistry(examples))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes[1].Properties["price"]).To(Equal("$$5 per ${ORG}"))
+			})
+			It("keeps the references to undefined variables", func() {
+				nodes, err = manifest.ResolveManifest("tests/examples/manifest_variables_undefined.yaml", repositoryhostsfakes.FilesystemRegistry(examples))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes[1].Source).To(Equal("/website/{{VERSION}}/readme.md"))
+			})
+			Context("references undefined variables strictly", func() {
+				BeforeEach(func() {
+					manifestURL = "tests/examples/manifest_variables_undefined.yaml"
+					interpolation.Vars = map[string]string{"ORG": "gardener"}
+					interpolation.Strict = true
+				})
+				It("fails", func() {
+					Expect(err).To(MatchError(ContainSubstring("undefined variables: VERSION")))
+				})
+			})
+		})
+		Context("manifest includes a manifest", func() {
+			var names func() []string
+			BeforeEach(func() {
+				names = func() []string {
+					var out []string
+					for _, node := range nodes {
+						if node.Type != "manifest" {
+							out = append(out, node.Name())
+						}
+					}
+					return out
+				}
+			})
+			It("inherits the variables of the including manifest", func() {
+				nodes, err = manifest.ResolveManifest("tests/examples/manifest_variables_include.yaml", repositoryhostsfakes.FilesystemRegistry(examples))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(names()).To(Equal([]string{"gardener", "guide-v3.md"}))
+			})
+			It("lets the provided variables take precedence", func() {
+				interpolation.Vars = map[string]string{"VERSION": "v2"}
+				nodes, err = manifest.ResolveManifestWithVars("tests/examples/manifest_variables_include.yaml", repositoryhostsfakes.FilesystemRegistry(examples), interpolation)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(names()).To(Equal([]string{"gardener", "guide-v2.md"}))
+			})
+		})
+		Context("no variables are provided", func() {
+			BeforeEach(func() {
+				interpolation.Vars = nil
+				Expect(os.Setenv("ORG", "env-org")).To(Succeed())
+				Expect(os.Setenv("VERSION", "env-version")).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(os.Unsetenv("ORG")).To(Succeed())
+				Expect(os.Unsetenv("VERSION")).To(Succeed())
+			})
+			It("uses the environment", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nodes[1].Dir).To(Equal("env-org"))
+				Expect(nodes[2].Source).To(Equal("/website/env-version/readme.md"))
+			})
+		})
 	})
 })
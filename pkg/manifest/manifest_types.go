@@ -35,12 +35,25 @@ type FilesTreeType struct {
 	FileTree string `yaml:"fileTree,omitempty"`
 	// ExcludeFiles files to be excluded
 	ExcludeFiles []string `yaml:"excludeFiles,omitempty"`
+	// ExcludeNames are the paths of files or directories, relative to the tree, to be excluded with all files below them
+	ExcludeNames []string `yaml:"excludeNames,omitempty"`
+	// IncludeExtensions are the extensions of the files to be included, "" matches the files without extension.
+	// Only the markdown and the extensionless files are included if empty
+	IncludeExtensions []string `yaml:"includeExtensions,omitempty"`
+	// IncludePattern is a regular expression, only the files with a path relative to the tree matching it are included
+	IncludePattern string `yaml:"includePattern,omitempty"`
 }
 
 // ManifType represents a manifest node
 type ManifType struct {
 	// Manifest is the manifest url
 	Manifest string `yaml:"manifest,omitempty"`
+	// Variables are substituted for `{{VAR}}` references in the node sources, names and properties of the
+	// manifest and the manifests it includes, which may override them
+	Variables map[string]string `yaml:"variables,omitempty"`
+	// DefaultProperties are merged into the properties of the manifest documents that don't define them,
+	// included manifests inherit the default properties they don't define
+	DefaultProperties map[string]interface{} `yaml:"defaultProperties,omitempty"`
 
 	manifest *Manifest
 }
@@ -5,9 +5,16 @@
 package manifest
 
 import (
+	"context"
+	"fmt"
+	"maps"
 	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 
+	resourcehandlers "github.com/gardener/docforge/pkg/readers/repositoryhosts"
 	"gopkg.in/yaml.v3"
 )
 
@@ -29,6 +36,38 @@ func (n *Node) NodePath() string {
 	return path.Join(n.Path, n.Name())
 }
 
+// PathSegments returns the names of the directories in the node Path from the root
+// to the parent of the node, it's empty for the nodes at the root
+func (n *Node) PathSegments() []string {
+	segments := []string{}
+	for _, segment := range strings.Split(path.Clean(n.Path), "/") {
+		if segment != "" && segment != "." {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// OutputExtensionProperty is the node property overriding the default extension of OutputPath
+const OutputExtensionProperty = "outputExtension"
+
+// OutputPath returns the slash-delimited path the node is written to, i.e. its NodePath with extension
+// appended unless the name already has it. The leading dot of extension is optional.
+// A string OutputExtensionProperty of the node overrides extension and replaces the `.md` extension of the name.
+func (n *Node) OutputPath(extension string) string {
+	name := n.Name()
+	if override, ok := n.Properties[OutputExtensionProperty].(string); ok && override != "" {
+		extension = override
+		if ext := path.Ext(name); strings.EqualFold(ext, ".md") && !strings.EqualFold(ext, "."+strings.TrimPrefix(extension, ".")) {
+			name = strings.TrimSuffix(name, ext)
+		}
+	}
+	if ext := strings.TrimPrefix(extension, "."); ext != "" && !strings.EqualFold(path.Ext(name), "."+ext) {
+		name = name + "." + ext
+	}
+	return path.Join(n.Path, name)
+}
+
 // HugoPrettyPath returns hugo pretty path
 func (n *Node) HugoPrettyPath() string {
 	name := n.Name()
@@ -37,16 +76,245 @@ func (n *Node) HugoPrettyPath() string {
 	return path.Join(n.Path, name) + "/"
 }
 
+// RelativePath returns the path of node to relative to the directory of n
+func (n *Node) RelativePath(to *Node) string {
+	rel, err := filepath.Rel(filepath.FromSlash(n.Path), filepath.FromSlash(to.NodePath()))
+	if err != nil {
+		return to.NodePath()
+	}
+	return filepath.ToSlash(rel)
+}
+
+// RelativePathToSource returns the link from the directory of n to sourcePath, a slash-delimited
+// path relative to the output root. A leading slash is ignored and paths outside the root are rejected.
+func (n *Node) RelativePathToSource(sourcePath string) (string, error) {
+	target := path.Clean(strings.TrimPrefix(sourcePath, "/"))
+	if sourcePath == "" || target == ".." || strings.HasPrefix(target, "../") {
+		return "", fmt.Errorf("source path %q is not in the output root", sourcePath)
+	}
+	rel, err := filepath.Rel(filepath.FromSlash(path.Clean(n.Path)), filepath.FromSlash(target))
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
 // HasContent returns true if the node is a document node
 func (n *Node) HasContent() bool {
 	return len(n.MultiSource) > 0 || len(n.Source) > 0
 }
 
+// IsDocument returns true if the node is a file node that is written as a document
+func (n *Node) IsDocument() bool {
+	return n.Type == "file"
+}
+
+// IsContainer returns true if the node groups other nodes, i.e. it is not a document
+func (n *Node) IsContainer() bool {
+	return !n.IsDocument()
+}
+
 // Parent is the node parent
 func (n *Node) Parent() *Node {
 	return n.parent
 }
 
+// Parents returns the ancestors of the node ordered from the root to the immediate parent
+func (n *Node) Parents() []*Node {
+	parents := make([]*Node, n.Depth())
+	i := len(parents) - 1
+	for p := n.parent; p != nil; p = p.parent {
+		parents[i] = p
+		i--
+	}
+	return parents
+}
+
+// Ancestor returns the nearest ancestor of the node with the given name or nil if there is none
+func (n *Node) Ancestor(name string) *Node {
+	for p := n.parent; p != nil; p = p.parent {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// SiblingIndex returns the zero-based index of n in its parent structure or -1 if it has no parent
+func (n *Node) SiblingIndex() int {
+	if n.parent == nil {
+		return -1
+	}
+	for i, sibling := range n.parent.Structure {
+		if sibling == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// NextSibling returns the node following n in its parent structure or nil if n is the last one
+func (n *Node) NextSibling() *Node {
+	if i := n.SiblingIndex(); i >= 0 && i+1 < len(n.parent.Structure) {
+		return n.parent.Structure[i+1]
+	}
+	return nil
+}
+
+// PrevSibling returns the node preceding n in its parent structure or nil if n is the first one
+func (n *Node) PrevSibling() *Node {
+	if i := n.SiblingIndex(); i > 0 {
+		return n.parent.Structure[i-1]
+	}
+	return nil
+}
+
+// Depth returns the number of ancestors of the node, 0 for a root node
+func (n *Node) Depth() int {
+	depth := 0
+	for p := n.parent; p != nil; p = p.parent {
+		depth++
+	}
+	return depth
+}
+
+// Navigation holds the relative links to the previous and next documents in reading order,
+// a link is empty if there is no such document
+type Navigation struct {
+	Prev string
+	Next string
+}
+
+// ReadingOrder returns the document nodes below n in depth-first order
+func (n *Node) ReadingOrder() []*Node {
+	var documents []*Node
+	for _, child := range n.Structure {
+		if child.IsDocument() {
+			documents = append(documents, child)
+			continue
+		}
+		documents = append(documents, child.ReadingOrder()...)
+	}
+	return documents
+}
+
+// Documents returns the document nodes of the subtree of n, including n, in reading order
+func (n *Node) Documents() []*Node {
+	if n.IsDocument() {
+		return []*Node{n}
+	}
+	return n.ReadingOrder()
+}
+
+// Counts returns the numbers of document and container nodes of the subtree of n, including n.
+// Subtract n itself for the counts below it, e.g. one container for a root node.
+func (n *Node) Counts() (documents int, containers int) {
+	if n.IsDocument() {
+		documents++
+	} else {
+		containers++
+	}
+	for _, child := range n.Structure {
+		d, c := child.Counts()
+		documents += d
+		containers += c
+	}
+	return documents, containers
+}
+
+// Navigation returns the previous and next document links of the document nodes below n
+func (n *Node) Navigation() map[*Node]Navigation {
+	documents := n.ReadingOrder()
+	navigation := make(map[*Node]Navigation, len(documents))
+	for i, document := range documents {
+		var nav Navigation
+		if i > 0 {
+			nav.Prev = document.RelativePath(documents[i-1])
+		}
+		if i+1 < len(documents) {
+			nav.Next = document.RelativePath(documents[i+1])
+		}
+		navigation[document] = nav
+	}
+	return navigation
+}
+
+// Breadcrumb is the name of an ancestor node and the link to it relative to a document
+type Breadcrumb struct {
+	Name string
+	Link string
+}
+
+// Breadcrumbs returns the breadcrumbs of the named ancestors of n ordered from the root
+func (n *Node) Breadcrumbs() []Breadcrumb {
+	var breadcrumbs []Breadcrumb
+	for _, p := range n.Parents() {
+		if p.Name() == "" {
+			continue
+		}
+		breadcrumbs = append(breadcrumbs, Breadcrumb{Name: p.Name(), Link: n.RelativePath(p)})
+	}
+	return breadcrumbs
+}
+
+// ToMermaid returns a Mermaid `graph TD` diagram of the node subtree.
+// File nodes are styled as documents, all other nodes as containers.
+func (n *Node) ToMermaid() string {
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+	b.WriteString("  classDef container fill:#e8eef7,stroke:#4a6fa5\n")
+	b.WriteString("  classDef document fill:#ffffff,stroke:#999999\n")
+	count := 0
+	var walk func(node *Node, parentID string)
+	walk = func(node *Node, parentID string) {
+		id := fmt.Sprintf("n%d", count)
+		count++
+		label := node.Name()
+		if label == "" {
+			label = node.Type
+		}
+		class := "container"
+		if node.IsDocument() {
+			class = "document"
+		}
+		fmt.Fprintf(&b, "  %s[\"%s\"]:::%s\n", id, strings.ReplaceAll(label, `"`, "#quot;"), class)
+		if parentID != "" {
+			fmt.Fprintf(&b, "  %s --> %s\n", parentID, id)
+		}
+		for _, child := range node.Structure {
+			walk(child, id)
+		}
+	}
+	walk(n, "")
+	return b.String()
+}
+
+// ToTOC renders the structure below n as a nested markdown list. Document nodes are links
+// to their node path appended to linkBase, a URL or a path, or relative to n when linkBase
+// is empty. Container nodes are bold entries.
+func (n *Node) ToTOC(linkBase string) string {
+	var b strings.Builder
+	var walk func(node *Node, depth int)
+	walk = func(node *Node, depth int) {
+		for _, child := range node.Structure {
+			indent := strings.Repeat("  ", depth)
+			if child.IsDocument() {
+				link := n.RelativePath(child)
+				if linkBase != "" {
+					// path.Join would collapse the double slash of a URL scheme
+					link = strings.TrimSuffix(linkBase, "/") + "/" + child.NodePath()
+				}
+				fmt.Fprintf(&b, "%s- [%s](%s)\n", indent, child.Name(), link)
+				continue
+			}
+			fmt.Fprintf(&b, "%s- **%s**\n", indent, child.Name())
+			walk(child, depth+1)
+		}
+	}
+	walk(n, 0)
+	return b.String()
+}
+
 func (n *Node) String() string {
 	node, err := yaml.Marshal(n)
 	if err != nil {
@@ -54,3 +322,467 @@ func (n *Node) String() string {
 	}
 	return string(node)
 }
+
+// Equal reports whether n and other have the same content recursively, ignoring their parents.
+// Nil and empty collections are considered equal.
+func (n *Node) Equal(other *Node) bool {
+	if n == nil || other == nil {
+		return n == other
+	}
+	if n.Type != other.Type || n.Path != other.Path ||
+		n.Manifest != other.Manifest || !maps.Equal(n.Variables, other.Variables) ||
+		!equalValues(n.DefaultProperties, other.DefaultProperties) ||
+		n.File != other.File || n.Source != other.Source || !slices.Equal(n.MultiSource, other.MultiSource) ||
+		n.Dir != other.Dir || n.FileTree != other.FileTree || !slices.Equal(n.ExcludeFiles, other.ExcludeFiles) ||
+		!slices.Equal(n.ExcludeNames, other.ExcludeNames) || !slices.Equal(n.IncludeExtensions, other.IncludeExtensions) ||
+		n.IncludePattern != other.IncludePattern ||
+		!equalValues(n.Properties, other.Properties) || !equalValues(n.Frontmatter, other.Frontmatter) {
+		return false
+	}
+	return slices.EqualFunc(n.Structure, other.Structure, (*Node).Equal)
+}
+
+func equalValues(a map[string]interface{}, b map[string]interface{}) bool {
+	return len(a) == 0 && len(b) == 0 || reflect.DeepEqual(a, b)
+}
+
+// Conflict describes a node that can't be merged because a node with the same name already exists
+type Conflict struct {
+	// Path is the path of the existing node
+	Path string
+	// Existing is the node already present in the structure
+	Existing *Node
+	// Incoming is the node that wasn't merged
+	Incoming *Node
+}
+
+// Merge merges the structure of other into n following the same collision rules as
+// manifest resolution: dir nodes with the same name are merged recursively, nodes with
+// names not present in n are appended, nodes equal to the existing ones are skipped and
+// any other name collision is reported as Conflict and the incoming node is skipped.
+// When dir nodes are merged, the frontmatter and properties of the existing node take
+// precedence and only keys missing in it are copied from the incoming node.
+func (n *Node) Merge(other *Node) []Conflict {
+	var conflicts []Conflict
+	for _, incoming := range other.Structure {
+		existing := n.childByName(incoming.Name())
+		switch {
+		case existing == nil:
+			incoming.parent = n
+			n.Structure = append(n.Structure, incoming)
+		case existing.Type == "dir" && incoming.Type == "dir":
+			existing.Frontmatter = mergeMissingKeys(existing.Frontmatter, incoming.Frontmatter)
+			existing.Properties = mergeMissingKeys(existing.Properties, incoming.Properties)
+			conflicts = append(conflicts, existing.Merge(incoming)...)
+		case existing.Equal(incoming):
+		default:
+			conflicts = append(conflicts, Conflict{Path: existing.NodePath(), Existing: existing, Incoming: incoming})
+		}
+	}
+	return conflicts
+}
+
+// Prune recursively removes the container nodes below n whose subtrees contain
+// no document nodes and returns the number of removed nodes
+func (n *Node) Prune() int {
+	removed := 0
+	structure := n.Structure[:0]
+	for _, child := range n.Structure {
+		if child.IsContainer() {
+			removed += child.Prune()
+			if len(child.Structure) == 0 {
+				removed++
+				continue
+			}
+		}
+		structure = append(structure, child)
+	}
+	n.Structure = structure
+	return removed
+}
+
+// Orphans returns the nodes that are not reachable by descending the structure of any
+// of the roots, e.g. nodes detached from their parents during structural edits
+func Orphans(roots []*Node, nodes []*Node) []*Node {
+	reachable := map[*Node]bool{}
+	var walk func(*Node)
+	walk = func(n *Node) {
+		if reachable[n] {
+			return
+		}
+		reachable[n] = true
+		for _, child := range n.Structure {
+			walk(child)
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+	var orphans []*Node
+	for _, node := range nodes {
+		if !reachable[node] {
+			orphans = append(orphans, node)
+		}
+	}
+	return orphans
+}
+
+// ChangeType is the kind of difference between two node trees reported by Diff
+type ChangeType string
+
+const (
+	// NodeAdded is the change of a node present only in the second tree
+	NodeAdded ChangeType = "added"
+	// NodeRemoved is the change of a node present only in the first tree
+	NodeRemoved ChangeType = "removed"
+	// NodeModified is the change of a node present in both trees with different content
+	NodeModified ChangeType = "modified"
+)
+
+// NodeChange is a node that differs between two node trees
+type NodeChange struct {
+	// Path is the slash-delimited path of the node names relative to the tree roots
+	Path string
+	Type ChangeType
+	// Old is the node of the first tree, nil for added nodes
+	Old *Node
+	// New is the node of the second tree, nil for removed nodes
+	New *Node
+}
+
+// Diff compares the file and dir nodes below the roots a and b and returns the added, removed and
+// modified nodes ordered by path. Nodes are matched by path, a renamed node is removed and added. Nodes are
+// modified if they are not Equal without their structures, changes below them are reported separately.
+func Diff(a, b *Node) []NodeChange {
+	old, updated := nodesByPath(a), nodesByPath(b)
+	var changes []NodeChange
+	for p, o := range old {
+		n, ok := updated[p]
+		if !ok {
+			changes = append(changes, NodeChange{Path: p, Type: NodeRemoved, Old: o})
+			continue
+		}
+		oc, nc := *o, *n
+		oc.Structure, nc.Structure = nil, nil
+		if !oc.Equal(&nc) {
+			changes = append(changes, NodeChange{Path: p, Type: NodeModified, Old: o, New: n})
+		}
+	}
+	for p, n := range updated {
+		if _, ok := old[p]; !ok {
+			changes = append(changes, NodeChange{Path: p, Type: NodeAdded, New: n})
+		}
+	}
+	slices.SortFunc(changes, func(x, y NodeChange) int {
+		return strings.Compare(x.Path, y.Path)
+	})
+	return changes
+}
+
+// nodesByPath maps the paths of the file and dir nodes below root to the nodes,
+// the first node wins for duplicate paths
+func nodesByPath(root *Node) map[string]*Node {
+	nodes := map[string]*Node{}
+	var walk func(*Node, string)
+	walk = func(n *Node, parentPath string) {
+		for _, child := range n.Structure {
+			p := parentPath
+			if name := child.Name(); name != "" {
+				p = path.Join(parentPath, name)
+				if _, ok := nodes[p]; !ok {
+					nodes[p] = child
+				}
+			}
+			walk(child, p)
+		}
+	}
+	if root != nil {
+		walk(root, "")
+	}
+	return nodes
+}
+
+// FilterByProperty returns a copy of the structure below n that contains only the nodes whose key
+// property matches value and their ancestors. A property matches if it equals value or if it is a
+// list containing value. The nodes of the copy have their own collections and property maps.
+func (n *Node) FilterByProperty(key string, value interface{}) *Node {
+	filtered := n.copy()
+	filtered.parent = nil
+	filtered.Structure = filterByProperty(n.Structure, filtered, key, value)
+	return filtered
+}
+
+func filterByProperty(structure []*Node, parent *Node, key string, value interface{}) []*Node {
+	var filtered []*Node
+	for _, child := range structure {
+		c := child.copy()
+		c.parent = parent
+		c.Structure = filterByProperty(child.Structure, c, key, value)
+		if len(c.Structure) > 0 || matchesProperty(child.Properties[key], value) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// matchesProperty checks if a property value equals value or is a list containing it
+func matchesProperty(property interface{}, value interface{}) bool {
+	if list, ok := property.([]interface{}); ok {
+		return slices.ContainsFunc(list, func(e interface{}) bool { return reflect.DeepEqual(e, value) })
+	}
+	return property != nil && reflect.DeepEqual(property, value)
+}
+
+// copy returns a shallow copy of n with its own collections and without structure
+func (n *Node) copy() *Node {
+	c := *n
+	c.Variables = maps.Clone(n.Variables)
+	c.DefaultProperties = maps.Clone(n.DefaultProperties)
+	c.MultiSource = slices.Clone(n.MultiSource)
+	c.ExcludeFiles = slices.Clone(n.ExcludeFiles)
+	c.ExcludeNames = slices.Clone(n.ExcludeNames)
+	c.IncludeExtensions = slices.Clone(n.IncludeExtensions)
+	c.Properties = maps.Clone(n.Properties)
+	c.Frontmatter = maps.Clone(n.Frontmatter)
+	c.Structure = nil
+	return &c
+}
+
+// NodeByPath returns the node at a slash-delimited path relative to n by matching
+// the names of the nodes at each segment, or nil if there is no such node
+func (n *Node) NodeByPath(p string) *Node {
+	node := n
+	for _, name := range strings.Split(strings.Trim(p, "/"), "/") {
+		if name == "" {
+			continue
+		}
+		if node = node.childByName(name); node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// DuplicateSources returns the sources referenced by more than one document node below n,
+// considering Source and MultiSource, mapped to the referencing nodes in structure order
+func (n *Node) DuplicateSources() map[string][]*Node {
+	referrers := map[string][]*Node{}
+	n.collectSources(referrers)
+	for source, nodes := range referrers {
+		if len(nodes) < 2 {
+			delete(referrers, source)
+		}
+	}
+	return referrers
+}
+
+func (n *Node) collectSources(referrers map[string][]*Node) {
+	for _, child := range n.Structure {
+		sources := child.MultiSource
+		if len(child.Source) > 0 {
+			sources = append([]string{child.Source}, sources...)
+		}
+		for _, source := range sources {
+			if nodes := referrers[source]; len(nodes) == 0 || nodes[len(nodes)-1] != child {
+				referrers[source] = append(nodes, child)
+			}
+		}
+		child.collectSources(referrers)
+	}
+}
+
+// CheckSources verifies that the sources of the document nodes below n can be read before
+// the documents are processed and returns an error per unresolved source in reading order
+func (n *Node) CheckSources(ctx context.Context, r resourcehandlers.Registry) []error {
+	var errs []error
+	checked := map[string]bool{}
+	for _, document := range n.ReadingOrder() {
+		sources := document.MultiSource
+		if len(document.Source) > 0 {
+			sources = append([]string{document.Source}, sources...)
+		}
+		for _, source := range sources {
+			// a fragment selects a section of the source
+			source, _, _ = strings.Cut(source, "#")
+			if checked[source] {
+				continue
+			}
+			checked[source] = true
+			repoHost, err := r.Get(source)
+			if err == nil {
+				_, err = repoHost.Read(ctx, source)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("source %s of node %s can't be read: %w", source, document.NodePath(), err))
+			}
+		}
+	}
+	return errs
+}
+
+// FileTrees returns n and the nodes below it that have a fileTree in structure order. Use it
+// on manifests that are not resolved yet, e.g. parsed with ParseMulti, as resolving expands them.
+func (n *Node) FileTrees() []*Node {
+	var fileTrees []*Node
+	if n.FileTree != "" {
+		fileTrees = append(fileTrees, n)
+	}
+	for _, child := range n.Structure {
+		fileTrees = append(fileTrees, child.FileTrees()...)
+	}
+	return fileTrees
+}
+
+// CheckFileTrees verifies that a repository host accepts the fileTree of each node returned
+// by FileTrees and returns an error per fileTree without one in structure order
+func (n *Node) CheckFileTrees(r resourcehandlers.Registry) []error {
+	var errs []error
+	for _, node := range n.FileTrees() {
+		if _, err := r.Get(node.FileTree); err != nil {
+			errs = append(errs, fmt.Errorf("fileTree %s is not accepted by any repository host: %w", node.FileTree, err))
+		}
+	}
+	return errs
+}
+
+// CheckUniqueNames verifies that the file and dir nodes of each container below n, including n,
+// have unique names as they are written to the same directory otherwise. It returns an error per
+// container with duplicate names in structure order, naming the container path and the duplicates.
+func (n *Node) CheckUniqueNames() []error {
+	var errs []error
+	var walk func(*Node, string)
+	walk = func(node *Node, nodePath string) {
+		seen := map[string]int{}
+		var duplicates []string
+		for _, child := range node.Structure {
+			name := child.Name()
+			if name == "" {
+				continue
+			}
+			if seen[name]++; seen[name] == 2 {
+				duplicates = append(duplicates, name)
+			}
+		}
+		if len(duplicates) > 0 {
+			errs = append(errs, fmt.Errorf("container %s has duplicate child names: %s", nodePath, strings.Join(duplicates, ", ")))
+		}
+		for _, child := range node.Structure {
+			walk(child, path.Join(nodePath, child.Name()))
+		}
+	}
+	root := "."
+	if n.Type == "dir" {
+		root = n.NodePath()
+	}
+	walk(n, root)
+	return errs
+}
+
+// Rename changes the name of a file or dir node. The name must be unique among the node siblings.
+// When a dir node is renamed the paths of all nodes below it are updated.
+func (n *Node) Rename(newName string) error {
+	if n.Type != "file" && n.Type != "dir" {
+		return fmt.Errorf("node of type %s can't be renamed", n.Type)
+	}
+	if newName == "" || strings.Contains(newName, "/") {
+		return fmt.Errorf("invalid node name %q", newName)
+	}
+	if n.parent != nil {
+		if sibling := n.parent.childByName(newName); sibling != nil && sibling != n {
+			return fmt.Errorf("node %s already exists", sibling.NodePath())
+		}
+	}
+	if n.Type == "file" {
+		n.File = newName
+		return nil
+	}
+	oldPath := n.NodePath()
+	n.Dir = newName
+	n.updatePaths(oldPath, n.NodePath())
+	return nil
+}
+
+// SetProperty sets a node property, initializing the properties if needed
+func (n *Node) SetProperty(key string, value interface{}) {
+	if n.Properties == nil {
+		n.Properties = map[string]interface{}{}
+	}
+	n.Properties[key] = value
+}
+
+// MergeProperties copies the entries of m into the node properties.
+// Existing properties are replaced only if override is true.
+func (n *Node) MergeProperties(m map[string]interface{}, override bool) {
+	if !override {
+		n.Properties = mergeMissingKeys(n.Properties, m)
+		return
+	}
+	for k, v := range m {
+		n.SetProperty(k, v)
+	}
+}
+
+// DisplayNameExtensions are the extensions stripped from node names by NormalizeName
+var DisplayNameExtensions = []string{".md"}
+
+// NormalizeName sets the node title property, used as display name, to the node name without
+// trailing slashes and DisplayNameExtensions. Titles that are already set, the name and the
+// sources of the node are not changed.
+func (n *Node) NormalizeName() {
+	if _, ok := n.Properties["title"]; ok {
+		return
+	}
+	name := strings.TrimRight(n.Name(), "/")
+	for _, ext := range DisplayNameExtensions {
+		if strings.HasSuffix(strings.ToLower(name), strings.ToLower(ext)) {
+			name = name[:len(name)-len(ext)]
+			break
+		}
+	}
+	if name != "" {
+		n.SetProperty("title", name)
+	}
+}
+
+// NormalizeNames normalizes the names of n and all nodes below it with NormalizeName
+func (n *Node) NormalizeNames() {
+	n.NormalizeName()
+	for _, child := range n.Structure {
+		child.NormalizeNames()
+	}
+}
+
+// updatePaths replaces the oldPath prefix of the paths of all nodes below n with newPath
+func (n *Node) updatePaths(oldPath string, newPath string) {
+	for _, child := range n.Structure {
+		if child.Path == oldPath || strings.HasPrefix(child.Path, oldPath+"/") {
+			child.Path = newPath + strings.TrimPrefix(child.Path, oldPath)
+		}
+		child.updatePaths(oldPath, newPath)
+	}
+}
+
+// childByName returns the child node with the given name or nil if there is none
+func (n *Node) childByName(name string) *Node {
+	if name == "" {
+		return nil
+	}
+	for _, child := range n.Structure {
+		if child.Name() == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func mergeMissingKeys(dst map[string]interface{}, src map[string]interface{}) map[string]interface{} {
+	for k, v := range src {
+		if dst == nil {
+			dst = map[string]interface{}{}
+		}
+		if _, ok := dst[k]; !ok {
+			dst[k] = v
+		}
+	}
+	return dst
+}
@@ -0,0 +1,757 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gardener/docforge/pkg/manifest"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/repositoryhostsfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func file(name string, source string) *manifest.Node {
+	return &manifest.Node{Type: "file", FileType: manifest.FileType{File: name, Source: source}}
+}
+
+func dir(name string, children ...*manifest.Node) *manifest.Node {
+	return &manifest.Node{Type: "dir", DirType: manifest.DirType{Dir: name, Structure: children}}
+}
+
+func root(children ...*manifest.Node) *manifest.Node {
+	return &manifest.Node{Type: "manifest", ManifType: manifest.ManifType{Manifest: "manifest.yaml"}, DirType: manifest.DirType{Structure: children}}
+}
+
+var _ = Describe("Node", func() {
+	Describe("#Merge", func() {
+		var (
+			a, b      *manifest.Node
+			conflicts []manifest.Conflict
+		)
+		BeforeEach(func() {
+			a = root(
+				dir("docs", file("one.md", "https://a/one.md"), file("same.md", "https://a/same.md")),
+				file("readme.md", "https://a/readme.md"),
+			)
+			a.Structure[0].Frontmatter = map[string]interface{}{"title": "A"}
+			b = root(
+				dir("docs", file("two.md", "https://b/two.md"), file("same.md", "https://b/same.md")),
+				dir("blog", file("post.md", "https://b/post.md")),
+			)
+			b.Structure[0].Frontmatter = map[string]interface{}{"title": "B", "weight": 1}
+		})
+		JustBeforeEach(func() {
+			conflicts = a.Merge(b)
+		})
+		It("merges overlapping dirs", func() {
+			docs := a.Structure[0]
+			Expect(docs.Structure).To(HaveLen(3))
+			Expect(docs.Structure[2].Name()).To(Equal("two.md"))
+			Expect(docs.Structure[2].Parent()).To(Equal(docs))
+			Expect(docs.Frontmatter).To(Equal(map[string]interface{}{"title": "A", "weight": 1}))
+		})
+		It("appends disjoint roots", func() {
+			Expect(a.Structure).To(HaveLen(3))
+			Expect(a.Structure[2].Name()).To(Equal("blog"))
+			Expect(a.Structure[2].Parent()).To(Equal(a))
+		})
+		It("reports conflicts", func() {
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].Existing.Source).To(Equal("https://a/same.md"))
+			Expect(conflicts[0].Incoming.Source).To(Equal("https://b/same.md"))
+		})
+		Context("equal nodes", func() {
+			BeforeEach(func() {
+				b.Structure[0].Structure[1].Source = "https://a/same.md"
+			})
+			It("skips them without conflict", func() {
+				Expect(conflicts).To(BeEmpty())
+				Expect(a.Structure[0].Structure).To(HaveLen(3))
+				Expect(a.Structure[0].Structure[1]).NotTo(BeIdenticalTo(b.Structure[0].Structure[1]))
+			})
+		})
+	})
+	Describe("#Equal", func() {
+		var a, b *manifest.Node
+		BeforeEach(func() {
+			a = dir("docs", file("one.md", "https://a/one.md"), dir("guides", file("two.md", "https://a/two.md")))
+			b = dir("docs", file("one.md", "https://a/one.md"), dir("guides", file("two.md", "https://a/two.md")))
+			a.Frontmatter = map[string]interface{}{}
+			root(a).SetParents()
+			root(dir("other", b)).SetParents()
+		})
+		It("ignores the parents", func() {
+			Expect(a.Parent()).NotTo(Equal(b.Parent()))
+			Expect(a.Equal(b)).To(BeTrue())
+			Expect(b.Equal(a)).To(BeTrue())
+		})
+		It("compares the structure recursively", func() {
+			b.Structure[1].Structure[0].Source = "https://b/two.md"
+			Expect(a.Equal(b)).To(BeFalse())
+		})
+		It("compares the properties", func() {
+			b.Properties = map[string]interface{}{"weight": 1}
+			Expect(a.Equal(b)).To(BeFalse())
+		})
+		It("compares the default properties", func() {
+			a.DefaultProperties = map[string]interface{}{}
+			Expect(a.Equal(b)).To(BeTrue())
+			b.DefaultProperties = map[string]interface{}{"layout": "docs"}
+			Expect(a.Equal(b)).To(BeFalse())
+		})
+		It("compares the file tree filters", func() {
+			a.Structure[0] = &manifest.Node{Type: "fileTree", FilesTreeType: manifest.FilesTreeType{FileTree: "https://a/tree", IncludePattern: `\.md$`}}
+			b.Structure[0] = &manifest.Node{Type: "fileTree", FilesTreeType: manifest.FilesTreeType{FileTree: "https://a/tree", IncludePattern: `\.md$`}}
+			Expect(a.Equal(b)).To(BeTrue())
+			b.Structure[0].IncludePattern = `^guides/`
+			Expect(a.Equal(b)).To(BeFalse())
+		})
+		It("handles nil nodes", func() {
+			Expect(a.Equal(nil)).To(BeFalse())
+			Expect((*manifest.Node)(nil).Equal(nil)).To(BeTrue())
+		})
+	})
+	DescribeTable("#IsDocument and #IsContainer",
+		func(n *manifest.Node, document bool) {
+			Expect(n.IsDocument()).To(Equal(document))
+			Expect(n.IsContainer()).To(Equal(!document))
+		},
+		Entry("file", file("one.md", "https://a/one.md"), true),
+		Entry("file without source", file("_index.md", ""), true),
+		Entry("multiSource file", &manifest.Node{Type: "file", FileType: manifest.FileType{File: "multi.md", MultiSource: []string{"https://a/one.md"}}}, true),
+		Entry("dir", dir("docs"), false),
+		Entry("manifest", root(), false),
+		Entry("fileTree", &manifest.Node{Type: "fileTree", FilesTreeType: manifest.FilesTreeType{FileTree: "https://a/tree"}}, false),
+	)
+	Describe("#ToMermaid", func() {
+		It("emits nodes and edges", func() {
+			n := dir("docs", file(`say "hi".md`, "https://a/hi.md"), dir("sub", file("one.md", "https://a/one.md")))
+			out := n.ToMermaid()
+			Expect(out).To(HavePrefix("graph TD\n"))
+			Expect(out).To(ContainSubstring(`n0["docs"]:::container`))
+			Expect(out).To(ContainSubstring(`n1["say #quot;hi#quot;.md"]:::document`))
+			Expect(out).To(ContainSubstring(`n2["sub"]:::container`))
+			Expect(out).To(ContainSubstring(`n3["one.md"]:::document`))
+			Expect(out).To(ContainSubstring("n0 --> n1\n"))
+			Expect(out).To(ContainSubstring("n0 --> n2\n"))
+			Expect(out).To(ContainSubstring("n2 --> n3\n"))
+		})
+	})
+	Describe("#ToTOC", func() {
+		It("emits a nested list", func() {
+			intro := file("intro.md", "https://a/intro.md")
+			setup := file("setup.md", "https://a/setup.md")
+			setup.Path = "guides"
+			advanced := file("advanced.md", "https://a/advanced.md")
+			advanced.Path = "guides/more"
+			n := root(intro, dir("guides", setup, dir("more", advanced)))
+			Expect(n.ToTOC("/docs")).To(Equal(`- [intro.md](/docs/intro.md)
+- **guides**
+  - [setup.md](/docs/guides/setup.md)
+  - **more**
+    - [advanced.md](/docs/guides/more/advanced.md)
+`))
+		})
+		It("appends the node paths to a URL base", func() {
+			setup := file("setup.md", "https://a/setup.md")
+			setup.Path = "guides"
+			n := root(dir("guides", setup))
+			Expect(n.ToTOC("https://host/docs/")).To(Equal(`- **guides**
+  - [setup.md](https://host/docs/guides/setup.md)
+`))
+		})
+		It("links relative to the node without a base", func() {
+			intro := file("intro.md", "https://a/intro.md")
+			setup := file("setup.md", "https://a/setup.md")
+			setup.Path = "guides"
+			n := root(intro, dir("guides", setup))
+			Expect(n.ToTOC("")).To(Equal(`- [intro.md](intro.md)
+- **guides**
+  - [setup.md](guides/setup.md)
+`))
+		})
+	})
+	Describe("#Depth", func() {
+		It("counts the ancestors", func() {
+			r := root(dir("a", dir("b", file("c.md", "https://a/c.md"))))
+			r.SetParents()
+			Expect(r.Depth()).To(Equal(0))
+			Expect(r.Structure[0].Depth()).To(Equal(1))
+			Expect(r.Structure[0].Structure[0].Depth()).To(Equal(2))
+			Expect(r.Structure[0].Structure[0].Structure[0].Depth()).To(Equal(3))
+		})
+	})
+	Describe("#Parents", func() {
+		It("returns the ancestors from the root", func() {
+			r := root(dir("a", dir("b", file("c.md", "https://a/c.md"))))
+			r.SetParents()
+			a := r.Structure[0]
+			b := a.Structure[0]
+			Expect(r.Parents()).To(BeEmpty())
+			Expect(b.Structure[0].Parents()).To(Equal([]*manifest.Node{r, a, b}))
+		})
+		It("handles deep structures", func() {
+			leaf := deepChain(100000)
+			parents := leaf.Parents()
+			Expect(parents).To(HaveLen(100000))
+			Expect(parents[0].Parent()).To(BeNil())
+			Expect(parents[len(parents)-1]).To(Equal(leaf.Parent()))
+		})
+	})
+	Describe("#Ancestor", func() {
+		var r *manifest.Node
+		BeforeEach(func() {
+			r = root(dir("v1", dir("guides", dir("v1", file("c.md", "https://a/c.md")))))
+			r.SetParents()
+		})
+		It("returns the nearest ancestor with the name", func() {
+			outer := r.Structure[0]
+			guides := outer.Structure[0]
+			inner := guides.Structure[0]
+			leaf := inner.Structure[0]
+			Expect(leaf.Ancestor("v1")).To(BeIdenticalTo(inner))
+			Expect(leaf.Ancestor("guides")).To(BeIdenticalTo(guides))
+			Expect(guides.Ancestor("v1")).To(BeIdenticalTo(outer))
+		})
+		It("returns nil for the root", func() {
+			Expect(r.Ancestor("v1")).To(BeNil())
+		})
+		It("returns nil if no ancestor has the name", func() {
+			leaf := r.Structure[0].Structure[0].Structure[0].Structure[0]
+			Expect(leaf.Ancestor("v2")).To(BeNil())
+			Expect(leaf.Ancestor("c.md")).To(BeNil())
+		})
+	})
+	Describe("#SiblingIndex", func() {
+		var (
+			first, middle, last *manifest.Node
+			n                   *manifest.Node
+		)
+		BeforeEach(func() {
+			first, middle, last = file("one.md", "https://a/one.md"), file("two.md", "https://a/two.md"), file("three.md", "https://a/three.md")
+			n = root(first, middle, last)
+			n.SetParents()
+		})
+		It("navigates from the first node", func() {
+			Expect(first.SiblingIndex()).To(Equal(0))
+			Expect(first.PrevSibling()).To(BeNil())
+			Expect(first.NextSibling()).To(Equal(middle))
+		})
+		It("navigates from a middle node", func() {
+			Expect(middle.SiblingIndex()).To(Equal(1))
+			Expect(middle.PrevSibling()).To(Equal(first))
+			Expect(middle.NextSibling()).To(Equal(last))
+		})
+		It("navigates from the last node", func() {
+			Expect(last.SiblingIndex()).To(Equal(2))
+			Expect(last.PrevSibling()).To(Equal(middle))
+			Expect(last.NextSibling()).To(BeNil())
+		})
+		It("handles nodes without parent", func() {
+			Expect(n.SiblingIndex()).To(Equal(-1))
+			Expect(n.PrevSibling()).To(BeNil())
+			Expect(n.NextSibling()).To(BeNil())
+		})
+	})
+	Describe("#Documents", func() {
+		It("returns only the documents in reading order", func() {
+			intro := file("intro.md", "https://a/intro.md")
+			index := file("_index.md", "")
+			setup := file("setup.md", "https://a/setup.md")
+			advanced := file("advanced.md", "https://a/advanced.md")
+			guides := dir("guides", index, setup, dir("more", advanced), dir("empty"))
+			n := root(intro, guides, dir("empty", dir("deeper")))
+			Expect(n.Documents()).To(Equal([]*manifest.Node{intro, index, setup, advanced}))
+			Expect(guides.Documents()).To(Equal([]*manifest.Node{index, setup, advanced}))
+			Expect(setup.Documents()).To(Equal([]*manifest.Node{setup}))
+			Expect(dir("empty").Documents()).To(BeEmpty())
+		})
+	})
+	Describe("#Counts", func() {
+		It("counts the documents and containers of the subtree", func() {
+			guides := dir("guides", file("_index.md", ""), file("setup.md", "https://a/setup.md"), dir("more", file("advanced.md", "https://a/advanced.md")), dir("empty"))
+			n := root(file("intro.md", "https://a/intro.md"), guides, dir("empty", dir("deeper")))
+			documents, containers := n.Counts()
+			Expect(documents).To(Equal(4))
+			Expect(containers).To(Equal(6))
+			documents, containers = guides.Counts()
+			Expect(documents).To(Equal(3))
+			Expect(containers).To(Equal(3))
+			documents, containers = guides.Structure[1].Counts()
+			Expect(documents).To(Equal(1))
+			Expect(containers).To(Equal(0))
+		})
+	})
+	Describe("#Navigation", func() {
+		It("chains the documents in reading order", func() {
+			intro := file("intro.md", "https://a/intro.md")
+			intro.Path = "."
+			setup := file("setup.md", "https://a/setup.md")
+			setup.Path = "guides"
+			advanced := file("advanced.md", "https://a/advanced.md")
+			advanced.Path = "guides/more"
+			faq := file("faq.md", "https://a/faq.md")
+			faq.Path = "."
+			n := root(intro, dir("guides", setup, dir("more", advanced), dir("empty")), faq)
+			Expect(n.ReadingOrder()).To(Equal([]*manifest.Node{intro, setup, advanced, faq}))
+			Expect(n.Navigation()).To(Equal(map[*manifest.Node]manifest.Navigation{
+				intro:    {Next: "guides/setup.md"},
+				setup:    {Prev: "../intro.md", Next: "more/advanced.md"},
+				advanced: {Prev: "../setup.md", Next: "../../faq.md"},
+				faq:      {Prev: "guides/more/advanced.md"},
+			}))
+		})
+	})
+	Describe("#PathSegments", func() {
+		DescribeTable("returns the directories of the node path",
+			func(nodePath string, want []string) {
+				n := file("one.md", "https://a/one.md")
+				n.Path = nodePath
+				Expect(n.PathSegments()).To(Equal(want))
+			},
+			Entry("nested node", "docs/guides/setup", []string{"docs", "guides", "setup"}),
+			Entry("node below the root", "docs", []string{"docs"}),
+			Entry("root node", ".", []string{}),
+			Entry("node without path", "", []string{}),
+			Entry("path with empty segments", "/docs//guides/", []string{"docs", "guides"}),
+		)
+	})
+	Describe("#OutputPath", func() {
+		DescribeTable("returns the path the node is written to",
+			func(name string, nodePath string, extension string, want string) {
+				n := file(name, "https://a/"+name)
+				n.Path = nodePath
+				Expect(n.OutputPath(extension)).To(Equal(want))
+			},
+			Entry("name without extension", "readme", "docs", "md", "docs/readme.md"),
+			Entry("name with the extension", "readme.md", "docs", "md", "docs/readme.md"),
+			Entry("name with the extension in other case", "README.MD", "docs", "md", "docs/README.MD"),
+			Entry("name with other extension", "v1.2", "docs", "md", "docs/v1.2.md"),
+			Entry("extension with leading dot", "readme", "docs", ".md", "docs/readme.md"),
+			Entry("no extension", "LICENSE", "docs", "", "docs/LICENSE"),
+			Entry("root node", "readme.md", ".", "md", "readme.md"),
+		)
+		DescribeTable("honors the outputExtension property",
+			func(name string, property interface{}, want string) {
+				n := file(name, "https://a/"+name)
+				n.Path = "docs"
+				n.Properties = map[string]interface{}{manifest.OutputExtensionProperty: property}
+				Expect(n.OutputPath("md")).To(Equal(want))
+			},
+			Entry("markdown name", "readme.md", "html", "docs/readme.html"),
+			Entry("name without extension", "readme", ".html", "docs/readme.html"),
+			Entry("name with the extension", "index.html", "html", "docs/index.html"),
+			Entry("markdown extension", "README.MD", "md", "docs/README.MD"),
+			Entry("empty property", "readme.md", "", "docs/readme.md"),
+			Entry("non-string property", "readme.md", 42, "docs/readme.md"),
+		)
+	})
+	Describe("#RelativePathToSource", func() {
+		DescribeTable("returns the link to the source path",
+			func(nodePath string, sourcePath string, want string) {
+				n := file("one.md", "https://a/one.md")
+				n.Path = nodePath
+				rel, err := n.RelativePathToSource(sourcePath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rel).To(Equal(want))
+			},
+			Entry("co-located", "docs", "docs/img.png", "img.png"),
+			Entry("nested below", "docs", "docs/assets/img.png", "assets/img.png"),
+			Entry("deeply divergent", "a/b/c", "x/y/z.png", "../../../x/y/z.png"),
+			Entry("root node", ".", "x/y/z.png", "x/y/z.png"),
+			Entry("leading slash", "docs/guides", "/assets/img.png", "../../assets/img.png"),
+		)
+		It("rejects paths outside the output root", func() {
+			n := file("one.md", "https://a/one.md")
+			n.Path = "docs"
+			_, err := n.RelativePathToSource("../img.png")
+			Expect(err).To(MatchError(ContainSubstring("is not in the output root")))
+			_, err = n.RelativePathToSource("")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+	Describe("#Breadcrumbs", func() {
+		It("links the named ancestors from the root", func() {
+			advanced := file("advanced.md", "https://a/advanced.md")
+			advanced.Path = "guides/more"
+			more := dir("more", advanced)
+			more.Path = "guides"
+			guides := dir("guides", more)
+			guides.Path = "."
+			r := root(guides)
+			r.SetParents()
+			Expect(advanced.Breadcrumbs()).To(Equal([]manifest.Breadcrumb{
+				{Name: "guides", Link: ".."},
+				{Name: "more", Link: "."},
+			}))
+			Expect(r.Breadcrumbs()).To(BeEmpty())
+		})
+	})
+	Describe("#Prune", func() {
+		It("removes containers without documents at any depth", func() {
+			n := root(
+				dir("empty"),
+				dir("docs", file("one.md", "https://a/one.md"), dir("nested", dir("deeper")), dir("sub", file("two.md", "https://a/two.md"))),
+				dir("only-dirs", dir("a"), dir("b", dir("c"))),
+				file("readme.md", "https://a/readme.md"),
+			)
+			Expect(n.Prune()).To(Equal(7))
+			Expect(n.Structure).To(HaveLen(2))
+			Expect(n.NodeByPath("docs/one.md")).NotTo(BeNil())
+			Expect(n.NodeByPath("docs/sub/two.md")).NotTo(BeNil())
+			Expect(n.NodeByPath("docs/nested")).To(BeNil())
+			Expect(n.NodeByPath("readme.md")).NotTo(BeNil())
+		})
+		It("keeps documents without content", func() {
+			n := root(dir("docs", file("_index.md", "")))
+			Expect(n.Prune()).To(Equal(0))
+			Expect(n.NodeByPath("docs/_index.md")).NotTo(BeNil())
+		})
+	})
+	Describe("Orphans", func() {
+		It("returns the nodes not reachable from any root", func() {
+			one := file("one.md", "https://a/one.md")
+			two := file("two.md", "https://a/two.md")
+			docs := dir("docs", one, two)
+			n := root(docs)
+			other := root(file("other.md", "https://a/other.md"))
+			stray := file("stray.md", "https://a/stray.md")
+			docs.Structure = docs.Structure[:1]
+			nodes := []*manifest.Node{n, docs, one, two, other, other.Structure[0], stray}
+			Expect(manifest.Orphans([]*manifest.Node{n, other}, nodes)).To(Equal([]*manifest.Node{two, stray}))
+			Expect(manifest.Orphans([]*manifest.Node{n}, nodes)).To(Equal([]*manifest.Node{two, other, other.Structure[0], stray}))
+			Expect(manifest.Orphans([]*manifest.Node{n}, []*manifest.Node{n, docs, one})).To(BeEmpty())
+		})
+	})
+	Describe("Diff", func() {
+		It("reports the added, removed and modified nodes by path", func() {
+			a := root(
+				file("overview.md", "https://a/overview.md"),
+				dir("guides", file("install.md", "https://a/install.md"), file("usage.md", "https://a/usage.md")),
+				file("faq.md", "https://a/faq.md"),
+			)
+			b := root(
+				file("overview.md", "https://a/overview.md"),
+				dir("guides", file("install.md", "https://a/setup.md"), file("usage.md", "https://a/usage.md"), file("tuning.md", "https://a/tuning.md")),
+				dir("reference", file("api.md", "https://a/api.md")),
+			)
+			changes := manifest.Diff(a, b)
+			Expect(changes).To(Equal([]manifest.NodeChange{
+				{Path: "faq.md", Type: manifest.NodeRemoved, Old: a.Structure[2]},
+				{Path: "guides/install.md", Type: manifest.NodeModified, Old: a.Structure[1].Structure[0], New: b.Structure[1].Structure[0]},
+				{Path: "guides/tuning.md", Type: manifest.NodeAdded, New: b.Structure[1].Structure[2]},
+				{Path: "reference", Type: manifest.NodeAdded, New: b.Structure[2]},
+				{Path: "reference/api.md", Type: manifest.NodeAdded, New: b.Structure[2].Structure[0]},
+			}))
+		})
+		It("reports a renamed node as removed and added", func() {
+			a := root(dir("docs", file("old.md", "https://a/doc.md")))
+			b := root(dir("docs", file("new.md", "https://a/doc.md")))
+			Expect(manifest.Diff(a, b)).To(Equal([]manifest.NodeChange{
+				{Path: "docs/new.md", Type: manifest.NodeAdded, New: b.Structure[0].Structure[0]},
+				{Path: "docs/old.md", Type: manifest.NodeRemoved, Old: a.Structure[0].Structure[0]},
+			}))
+		})
+		It("reports no changes for equal trees", func() {
+			a := root(dir("docs", file("one.md", "https://a/one.md")))
+			b := root(dir("docs", file("one.md", "https://a/one.md")))
+			Expect(manifest.Diff(a, b)).To(BeEmpty())
+			Expect(manifest.Diff(nil, nil)).To(BeEmpty())
+		})
+	})
+	Describe("#FilterByProperty", func() {
+		var n *manifest.Node
+		tagged := func(node *manifest.Node, audience interface{}) *manifest.Node {
+			node.SetProperty("audience", audience)
+			return node
+		}
+		BeforeEach(func() {
+			n = root(
+				tagged(file("overview.md", "https://a/overview.md"), []interface{}{"operators", "developers"}),
+				dir("operations",
+					tagged(file("deploy.md", "https://a/deploy.md"), "operators"),
+					dir("monitoring", tagged(file("alerts.md", "https://a/alerts.md"), "operators")),
+				),
+				dir("development",
+					tagged(file("setup.md", "https://a/setup.md"), "developers"),
+					dir("testing", tagged(file("unit.md", "https://a/unit.md"), "developers")),
+				),
+				file("untagged.md", "https://a/untagged.md"),
+			)
+			n.SetParents()
+		})
+		It("keeps the matching nodes and their ancestors", func() {
+			filtered := n.FilterByProperty("audience", "operators")
+			Expect(filtered.NodeByPath("overview.md")).NotTo(BeNil())
+			Expect(filtered.NodeByPath("operations/deploy.md")).NotTo(BeNil())
+			Expect(filtered.NodeByPath("operations/monitoring/alerts.md")).NotTo(BeNil())
+			Expect(filtered.NodeByPath("development")).To(BeNil())
+			Expect(filtered.NodeByPath("untagged.md")).To(BeNil())
+			Expect(filtered.ReadingOrder()).To(HaveLen(3))
+			Expect(filtered.NodeByPath("operations/monitoring/alerts.md").Parent()).To(BeIdenticalTo(filtered.NodeByPath("operations/monitoring")))
+		})
+		It("copies the structure", func() {
+			filtered := n.FilterByProperty("audience", "developers")
+			Expect(filtered).NotTo(BeIdenticalTo(n))
+			setup := filtered.NodeByPath("development/setup.md")
+			Expect(setup).NotTo(BeIdenticalTo(n.NodeByPath("development/setup.md")))
+			setup.SetProperty("audience", "changed")
+			Expect(n.NodeByPath("development/setup.md").Properties["audience"]).To(Equal("developers"))
+			Expect(n.NodeByPath("operations/deploy.md")).NotTo(BeNil())
+		})
+		It("returns the root without structure if nothing matches", func() {
+			filtered := n.FilterByProperty("audience", "managers")
+			Expect(filtered.Structure).To(BeEmpty())
+			Expect(filtered.Manifest).To(Equal("manifest.yaml"))
+		})
+	})
+	Describe("#NodeByPath", func() {
+		var n *manifest.Node
+		BeforeEach(func() {
+			n = root(dir("docs", file("one.md", "https://a/one.md"), dir("sub", file("two.md", "https://a/two.md"))), file("readme.md", "https://a/readme.md"))
+		})
+		It("resolves existing paths", func() {
+			Expect(n.NodeByPath("docs/sub/two.md")).To(Equal(n.Structure[0].Structure[1].Structure[0]))
+			Expect(n.NodeByPath("/readme.md")).To(Equal(n.Structure[1]))
+			Expect(n.NodeByPath("")).To(Equal(n))
+		})
+		It("resolves a partial path to a container", func() {
+			Expect(n.NodeByPath("docs/sub/")).To(Equal(n.Structure[0].Structure[1]))
+		})
+		It("returns nil for a non-existent path", func() {
+			Expect(n.NodeByPath("docs/missing/two.md")).To(BeNil())
+			Expect(n.NodeByPath("readme.md/one.md")).To(BeNil())
+		})
+	})
+	Describe("#Rename", func() {
+		var (
+			docs *manifest.Node
+			n    *manifest.Node
+		)
+		BeforeEach(func() {
+			docs = dir("docs", file("one.md", "https://a/one.md"), file("two.md", "https://a/two.md"), dir("sub", file("three.md", "https://a/three.md")))
+			docs.Path = "."
+			docs.Structure[0].Path = "docs"
+			docs.Structure[1].Path = "docs"
+			docs.Structure[2].Path = "docs"
+			docs.Structure[2].Structure[0].Path = "docs/sub"
+			n = root(docs)
+			n.SetParents()
+		})
+		It("renames a file", func() {
+			Expect(docs.Structure[0].Rename("first.md")).To(Succeed())
+			Expect(docs.Structure[0].NodePath()).To(Equal("docs/first.md"))
+		})
+		It("renames a dir and updates the paths below it", func() {
+			Expect(docs.Rename("documentation")).To(Succeed())
+			Expect(docs.NodePath()).To(Equal("documentation"))
+			Expect(docs.Structure[0].NodePath()).To(Equal("documentation/one.md"))
+			Expect(docs.Structure[2].Structure[0].NodePath()).To(Equal("documentation/sub/three.md"))
+		})
+		It("rejects a name used by a sibling", func() {
+			err := docs.Structure[0].Rename("two.md")
+			Expect(err).To(MatchError("node docs/two.md already exists"))
+			Expect(docs.Structure[0].Name()).To(Equal("one.md"))
+		})
+		It("rejects invalid names", func() {
+			Expect(docs.Structure[0].Rename("")).NotTo(Succeed())
+			Expect(docs.Structure[0].Rename("a/b.md")).NotTo(Succeed())
+			Expect(n.Rename("root")).NotTo(Succeed())
+		})
+	})
+	Describe("#DuplicateSources", func() {
+		It("maps shared sources to the referencing nodes", func() {
+			one := file("one.md", "https://a/one.md")
+			copyOfOne := file("copy.md", "https://a/one.md")
+			multi := file("multi.md", "")
+			multi.MultiSource = []string{"https://a/two.md", "https://a/one.md", "https://a/two.md"}
+			two := file("two.md", "https://a/two.md")
+			n := root(one, dir("docs", copyOfOne, multi), two, file("three.md", "https://a/three.md"))
+			Expect(n.DuplicateSources()).To(Equal(map[string][]*manifest.Node{
+				"https://a/one.md": {one, copyOfOne, multi},
+				"https://a/two.md": {multi, two},
+			}))
+		})
+		It("returns no sources if there are no duplicates", func() {
+			n := root(file("one.md", "https://a/one.md"), file("two.md", "https://a/two.md"))
+			Expect(n.DuplicateSources()).To(BeEmpty())
+		})
+	})
+	Describe("#CheckSources", func() {
+		It("reports the sources that can't be read", func() {
+			host := &repositoryhostsfakes.FakeRepositoryHost{}
+			host.ReadCalls(func(_ context.Context, source string) ([]byte, error) {
+				if source == "https://a/dangling.md" {
+					return nil, repositoryhosts.ErrResourceNotFound(source)
+				}
+				return []byte("# Doc"), nil
+			})
+			registry := &repositoryhostsfakes.FakeRegistry{}
+			registry.GetCalls(func(source string) (repositoryhosts.RepositoryHost, error) {
+				if source == "ftp://b/other.md" {
+					return nil, errors.New("no sutiable repository host for ftp://b/other.md")
+				}
+				return host, nil
+			})
+			dangling := file("dangling.md", "https://a/dangling.md")
+			dangling.MultiSource = []string{"https://a/one.md#usage", "ftp://b/other.md"}
+			r := root(dir("docs", file("one.md", "https://a/one.md"), dangling))
+			r.SetParents()
+			errs := r.CheckSources(context.TODO(), registry)
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0]).To(MatchError(repositoryhosts.ErrResourceNotFound("https://a/dangling.md")))
+			Expect(errs[0]).To(MatchError(ContainSubstring("source https://a/dangling.md of node dangling.md")))
+			Expect(errs[1]).To(MatchError(ContainSubstring("ftp://b/other.md")))
+			Expect(host.ReadCallCount()).To(Equal(2))
+		})
+	})
+	Describe("#FileTrees", func() {
+		var n *manifest.Node
+		fileTree := func(tree string) *manifest.Node {
+			return &manifest.Node{FilesTreeType: manifest.FilesTreeType{FileTree: tree}}
+		}
+		BeforeEach(func() {
+			n = root(
+				fileTree("https://github.com/gardener/docforge/tree/master/docs"),
+				dir("api", fileTree("https://github.com/gardener/docforge/tree/master/api"), file("one.md", "https://a/one.md")),
+				dir("typo", dir("deeper", fileTree("https://gitub.com/gardener/docforge/tree/master/typo"))),
+			)
+		})
+		It("returns all nodes with a fileTree", func() {
+			var trees []string
+			for _, node := range n.FileTrees() {
+				trees = append(trees, node.FileTree)
+			}
+			Expect(trees).To(Equal([]string{
+				"https://github.com/gardener/docforge/tree/master/docs",
+				"https://github.com/gardener/docforge/tree/master/api",
+				"https://gitub.com/gardener/docforge/tree/master/typo",
+			}))
+			Expect(file("one.md", "https://a/one.md").FileTrees()).To(BeEmpty())
+		})
+		It("reports the fileTrees no repository host accepts", func() {
+			host := &repositoryhostsfakes.FakeRepositoryHost{}
+			host.AcceptCalls(func(link string) bool {
+				return strings.HasPrefix(link, "https://github.com/")
+			})
+			errs := n.CheckFileTrees(repositoryhosts.NewRegistry(host))
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0]).To(MatchError(ContainSubstring("fileTree https://gitub.com/gardener/docforge/tree/master/typo is not accepted")))
+			Expect(host.AcceptCallCount()).To(Equal(3))
+		})
+	})
+	Describe("#CheckUniqueNames", func() {
+		It("reports the containers with duplicate child names", func() {
+			n := root(
+				file("readme.md", "https://a/readme.md"),
+				dir("docs",
+					file("guide.md", "https://a/guide.md"),
+					file("faq.md", "https://a/faq.md"),
+					file("guide.md", "https://b/guide.md"),
+					dir("faq.md"),
+					file("guide.md", "https://c/guide.md"),
+				),
+				dir("api", file("index.md", "https://a/api.md")),
+				dir("api", dir("v1", file("one.md", "https://a/one.md"), file("one.md", "https://b/one.md"))),
+			)
+			errs := n.CheckUniqueNames()
+			Expect(errs).To(HaveLen(3))
+			Expect(errs[0]).To(MatchError("container . has duplicate child names: api"))
+			Expect(errs[1]).To(MatchError("container docs has duplicate child names: guide.md, faq.md"))
+			Expect(errs[2]).To(MatchError("container api/v1 has duplicate child names: one.md"))
+		})
+		It("returns no errors for unique names", func() {
+			n := root(
+				file("readme.md", "https://a/readme.md"),
+				dir("docs", file("readme.md", "https://b/readme.md"), dir("guides", file("readme.md", "https://c/readme.md"))),
+			)
+			Expect(n.CheckUniqueNames()).To(BeEmpty())
+		})
+	})
+	Describe("#NormalizeNames", func() {
+		It("sets display names without extensions and keeps the sources", func() {
+			one := file("one.md", "https://a/one.md")
+			two := file("TWO.MD", "https://a/two.md")
+			three := file("three", "https://a/three")
+			titled := file("titled.md", "https://a/titled.md")
+			titled.SetProperty("title", "Custom.md")
+			image := file("logo.png", "https://a/logo.png")
+			docs := dir("docs/", one, dir("guides", two, three), titled, image)
+			n := root(docs)
+			n.NormalizeNames()
+			Expect(n.Properties).NotTo(HaveKey("title"))
+			titles := map[string]interface{}{}
+			for _, node := range []*manifest.Node{docs, docs.Structure[1], one, two, three, titled, image} {
+				titles[node.Name()] = node.Properties["title"]
+			}
+			Expect(titles).To(Equal(map[string]interface{}{
+				"docs/":     "docs",
+				"guides":    "guides",
+				"one.md":    "one",
+				"TWO.MD":    "TWO",
+				"three":     "three",
+				"titled.md": "Custom.md",
+				"logo.png":  "logo.png",
+			}))
+			Expect(one.File).To(Equal("one.md"))
+			Expect(one.Source).To(Equal("https://a/one.md"))
+			Expect(two.Source).To(Equal("https://a/two.md"))
+		})
+	})
+	Describe("#SetProperty", func() {
+		It("initializes nil properties", func() {
+			n := file("one.md", "https://a/one.md")
+			n.SetProperty("title", "One")
+			Expect(n.Properties).To(Equal(map[string]interface{}{"title": "One"}))
+		})
+	})
+	Describe("#MergeProperties", func() {
+		var n *manifest.Node
+		BeforeEach(func() {
+			n = file("one.md", "https://a/one.md")
+			n.Properties = map[string]interface{}{"title": "One", "weight": 1}
+		})
+		It("keeps existing properties without override", func() {
+			n.MergeProperties(map[string]interface{}{"title": "Two", "draft": true}, false)
+			Expect(n.Properties).To(Equal(map[string]interface{}{"title": "One", "weight": 1, "draft": true}))
+		})
+		It("replaces existing properties with override", func() {
+			n.MergeProperties(map[string]interface{}{"title": "Two", "draft": true}, true)
+			Expect(n.Properties).To(Equal(map[string]interface{}{"title": "Two", "weight": 1, "draft": true}))
+		})
+		It("initializes nil properties", func() {
+			n.Properties = nil
+			n.MergeProperties(map[string]interface{}{"draft": true}, false)
+			Expect(n.Properties).To(Equal(map[string]interface{}{"draft": true}))
+		})
+	})
+})
+
+func deepChain(depth int) *manifest.Node {
+	r := dir("0")
+	n := r
+	for i := 1; i <= depth; i++ {
+		child := dir(strconv.Itoa(i))
+		n.Structure = []*manifest.Node{child}
+		n = child
+	}
+	r.SetParents()
+	return n
+}
+
+func BenchmarkParents(b *testing.B) {
+	leaf := deepChain(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		leaf.Parents()
+	}
+}
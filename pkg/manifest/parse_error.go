@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlErrorLine matches the line reported in yaml syntax and unmarshal errors
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// ParseError is a manifest yaml error with the line of the malformed content,
+// the yaml errors don't report columns
+type ParseError struct {
+	// Manifest is the manifest URL
+	Manifest string
+	// Line is the 1-based line of the error
+	Line int
+	// Snippet is the manifest content around Line
+	Snippet string
+	// Err is the original yaml error
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s:%d: %v\n%s", e.Manifest, e.Line, e.Err, e.Snippet)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError locates the line reported by a yaml error in the manifest content.
+// The error is returned unchanged if it doesn't report a line in the content.
+func newParseError(manifest string, content string, err error) error {
+	m := yamlErrorLine.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	lines := strings.Split(content, "\n")
+	line, _ := strconv.Atoi(m[1])
+	if line < 1 || line > len(lines) {
+		return err
+	}
+	var snippet strings.Builder
+	for i := max(line-2, 1); i <= min(line+1, len(lines)); i++ {
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&snippet, "%s %4d | %s\n", marker, i, lines[i-1])
+	}
+	return &ParseError{Manifest: manifest, Line: line, Snippet: snippet.String(), Err: err}
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package repositoryhosts
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// Reader reads resource content
+type Reader interface {
+	// Read a resource content at uri into a byte array
+	Read(ctx context.Context, resourceURL string) ([]byte, error)
+}
+
+// CachingReader memoizes the content returned by a Reader by resource URL.
+// The cache is bounded by the total size of the cached content and the least
+// recently read entries are evicted first. The returned content is shared
+// between callers and must not be modified.
+type CachingReader struct {
+	reader   Reader
+	maxBytes int
+	size     int
+	entries  map[string]*list.Element
+	lru      *list.List
+	hits     int
+	misses   int
+	mux      sync.Mutex
+}
+
+type cacheEntry struct {
+	resourceURL string
+	content     []byte
+}
+
+// NewCachingReader creates a CachingReader holding up to maxBytes of content read by reader
+func NewCachingReader(reader Reader, maxBytes int) *CachingReader {
+	return &CachingReader{
+		reader:   reader,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Read returns the cached content for resourceURL or reads it from the underlying reader
+func (c *CachingReader) Read(ctx context.Context, resourceURL string) ([]byte, error) {
+	c.mux.Lock()
+	if e, ok := c.entries[resourceURL]; ok {
+		c.lru.MoveToFront(e)
+		c.hits++
+		c.mux.Unlock()
+		return e.Value.(*cacheEntry).content, nil
+	}
+	c.misses++
+	c.mux.Unlock()
+	content, err := c.reader.Read(ctx, resourceURL)
+	if err != nil {
+		return nil, err
+	}
+	c.add(resourceURL, content)
+	return content, nil
+}
+
+// Stats returns the number of cache hits and misses
+func (c *CachingReader) Stats() (hits int, misses int) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.hits, c.misses
+}
+
+func (c *CachingReader) add(resourceURL string, content []byte) {
+	if len(content) > c.maxBytes {
+		return
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if _, ok := c.entries[resourceURL]; ok {
+		return
+	}
+	for c.size+len(content) > c.maxBytes {
+		oldest := c.lru.Back()
+		entry := oldest.Value.(*cacheEntry)
+		c.lru.Remove(oldest)
+		delete(c.entries, entry.resourceURL)
+		c.size -= len(entry.content)
+	}
+	c.entries[resourceURL] = c.lru.PushFront(&cacheEntry{resourceURL, content})
+	c.size += len(content)
+}
+
+type cachingRepositoryHost struct {
+	RepositoryHost
+	reader *CachingReader
+}
+
+// NewCachingRepositoryHost wraps a RepositoryHost so its Read results are cached up to maxBytes
+func NewCachingRepositoryHost(host RepositoryHost, maxBytes int) RepositoryHost {
+	return &cachingRepositoryHost{
+		RepositoryHost: host,
+		reader:         NewCachingReader(host, maxBytes),
+	}
+}
+
+func (c *cachingRepositoryHost) Read(ctx context.Context, resourceURL string) ([]byte, error) {
+	return c.reader.Read(ctx, resourceURL)
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package repositoryhosts_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/repositoryhostsfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRepositoryHosts(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Repository Hosts Suite")
+}
+
+var _ = Describe("CachingReader", func() {
+	var (
+		host   *repositoryhostsfakes.FakeRepositoryHost
+		reader *repositoryhosts.CachingReader
+		ctx    context.Context
+	)
+	BeforeEach(func() {
+		host = &repositoryhostsfakes.FakeRepositoryHost{}
+		host.ReadCalls(func(ctx context.Context, url string) ([]byte, error) {
+			if url == "missing" {
+				return nil, errors.New("fake_error")
+			}
+			return []byte(url), nil
+		})
+		reader = repositoryhosts.NewCachingReader(host, 10)
+		ctx = context.Background()
+	})
+
+	It("returns cached content on repeated reads", func() {
+		for i := 0; i < 3; i++ {
+			cnt, err := reader.Read(ctx, "aaaa")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(cnt)).To(Equal("aaaa"))
+		}
+		Expect(host.ReadCallCount()).To(Equal(1))
+		hits, misses := reader.Stats()
+		Expect(hits).To(Equal(2))
+		Expect(misses).To(Equal(1))
+	})
+
+	It("evicts the least recently read entries past the byte limit", func() {
+		for _, url := range []string{"aaaa", "bbbb", "aaaa", "cccc", "aaaa", "bbbb"} {
+			_, err := reader.Read(ctx, url)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(host.ReadCallCount()).To(Equal(4))
+		_, url := host.ReadArgsForCall(3)
+		Expect(url).To(Equal("bbbb"))
+	})
+
+	It("doesn't cache errors and content bigger than the limit", func() {
+		_, err := reader.Read(ctx, "missing")
+		Expect(err).To(HaveOccurred())
+		_, err = reader.Read(ctx, "missing")
+		Expect(err).To(HaveOccurred())
+		_, _ = reader.Read(ctx, "very_long_url")
+		_, _ = reader.Read(ctx, "very_long_url")
+		Expect(host.ReadCallCount()).To(Equal(4))
+	})
+
+	It("wraps a repository host", func() {
+		cached := repositoryhosts.NewCachingRepositoryHost(host, 10)
+		host.NameReturns("fake")
+		_, _ = cached.Read(ctx, "aaaa")
+		_, _ = cached.Read(ctx, "aaaa")
+		Expect(host.ReadCallCount()).To(Equal(1))
+		Expect(cached.Name()).To(Equal("fake"))
+	})
+})
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gardener/docforge/pkg/osfakes/httpclient"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+)
+
+const fileScheme = "file://"
+
+// Local is a repositoryhosts.RepositoryHost for the files of the local file system.
+// It accepts absolute file paths and file:// URLs, relative links are resolved
+// against the directory of the file referencing them.
+type Local struct{}
+
+// New creates a repository host for the local file system
+func New() repositoryhosts.RepositoryHost {
+	return &Local{}
+}
+
+// Name returns the repository host name
+func (l *Local) Name() string {
+	return "filesystem"
+}
+
+// Accept implements the repositoryhosts.RepositoryHost#Accept
+func (l *Local) Accept(link string) bool {
+	if strings.HasPrefix(link, fileScheme) {
+		return true
+	}
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "" {
+		return false
+	}
+	return filepath.IsAbs(link)
+}
+
+// Read implements the repositoryhosts.RepositoryHost#Read
+func (l *Local) Read(_ context.Context, resourceURL string) ([]byte, error) {
+	fn := filePath(resourceURL)
+	cnt, err := os.ReadFile(fn)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, repositoryhosts.NotFound(resourceURL, err)
+		}
+		return nil, fmt.Errorf("reading file %s fails: %v", fn, err)
+	}
+	return cnt, nil
+}
+
+// Tree implements the repositoryhosts.RepositoryHost#Tree returning the slash-delimited
+// paths of the markdown and ignore files below the resourceURL directory
+func (l *Local) Tree(resourceURL string) ([]string, error) {
+	dirPath := filePath(resourceURL)
+	files := []string{}
+	err := filepath.WalkDir(dirPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && (strings.HasSuffix(p, ".md") || d.Name() == repositoryhosts.IgnoreFileName) {
+			rel, err := filepath.Rel(dirPath, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, repositoryhosts.NotFound(resourceURL, err)
+		}
+		return nil, fmt.Errorf("listing directory %s fails: %v", dirPath, err)
+	}
+	return files, nil
+}
+
+// ToAbsLink implements the repositoryhosts.RepositoryHost#ToAbsLink
+func (l *Local) ToAbsLink(source, link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "" || filepath.IsAbs(link) {
+		return link, nil
+	}
+	if u.Path == "" {
+		return source + link, nil
+	}
+	prefix := ""
+	if strings.HasPrefix(source, fileScheme) {
+		prefix, source = fileScheme, strings.TrimPrefix(source, fileScheme)
+	}
+	abs := prefix + path.Join(path.Dir(filepath.ToSlash(source)), u.Path)
+	if u.RawQuery != "" {
+		abs += "?" + u.RawQuery
+	}
+	if u.Fragment != "" {
+		abs += "#" + u.Fragment
+	}
+	return abs, nil
+}
+
+// ReadGitInfo implements the repositoryhosts.RepositoryHost#ReadGitInfo, local files have no git info
+func (l *Local) ReadGitInfo(_ context.Context, _ string) ([]byte, error) {
+	return nil, nil
+}
+
+// GetRawFormatLink implements the repositoryhosts.RepositoryHost#GetRawFormatLink, local files are already raw
+func (l *Local) GetRawFormatLink(link string) (string, error) {
+	return link, nil
+}
+
+// GetClient implements the repositoryhosts.RepositoryHost#GetClient
+func (l *Local) GetClient() httpclient.Client {
+	return nil
+}
+
+// GetRateLimit implements the repositoryhosts.RepositoryHost#GetRateLimit, the local file system is not rate limited
+func (l *Local) GetRateLimit(_ context.Context) (int, int, time.Time, error) {
+	return -1, -1, time.Now(), nil
+}
+
+// filePath returns the file system path of a file path or file:// URL
+func filePath(resourceURL string) string {
+	if p, ok := strings.CutPrefix(resourceURL, fileScheme); ok {
+		if u, err := url.Parse(resourceURL); err == nil {
+			p = u.Path
+		}
+		return filepath.FromSlash(p)
+	}
+	if i := strings.IndexAny(resourceURL, "?#"); i >= 0 {
+		resourceURL = resourceURL[:i]
+	}
+	return filepath.FromSlash(resourceURL)
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package filesystem_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/filesystem"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func TestFilesystem(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Filesystem Suite")
+}
+
+var _ = Describe("Local", func() {
+	var (
+		dir  string
+		host repositoryhosts.RepositoryHost
+	)
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "filesystem")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(dir, "docs", "nested"), os.ModePerm)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "docs", "one.md"), []byte("one"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "docs", "nested", "two.md"), []byte("two"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "docs", "image.png"), []byte("png"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "docs", "nested", ".docforgeignore"), []byte("*.tmp.md"), 0644)).To(Succeed())
+		host = filesystem.New()
+	})
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	DescribeTable("#Accept",
+		func(link string, expected bool) {
+			Expect(host.Accept(link)).To(Equal(expected))
+		},
+		Entry("absolute path", "/docs/manifest.yaml", true),
+		Entry("file URL", "file:///docs/manifest.yaml", true),
+		Entry("relative path", "docs/manifest.yaml", false),
+		Entry("https URL", "https://github.com/gardener/docforge/blob/master/README.md", false),
+		Entry("mailto link", "mailto:a@b.c", false),
+	)
+
+	Describe("#Read", func() {
+		It("reads files by path and file URL", func() {
+			content, err := host.Read(context.TODO(), filepath.Join(dir, "docs", "one.md"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("one"))
+			content, err = host.Read(context.TODO(), "file://"+filepath.ToSlash(filepath.Join(dir, "docs", "one.md")))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("one"))
+		})
+		It("reports missing files as not found", func() {
+			_, err := host.Read(context.TODO(), filepath.Join(dir, "missing.md"))
+			Expect(errors.Is(err, repositoryhosts.ErrNotFound)).To(BeTrue())
+			Expect(errors.Is(err, fs.ErrNotExist)).To(BeTrue())
+			var notFound repositoryhosts.ErrResourceNotFound
+			Expect(errors.As(err, &notFound)).To(BeTrue())
+		})
+	})
+
+	Describe("#Tree", func() {
+		It("lists the markdown and ignore files", func() {
+			files, err := host.Tree(filepath.Join(dir, "docs"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(ConsistOf("one.md", "nested/two.md", "nested/.docforgeignore"))
+		})
+	})
+
+	DescribeTable("#ToAbsLink",
+		func(source, link, expected string) {
+			abs, err := host.ToAbsLink(source, link)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(abs).To(Equal(expected))
+		},
+		Entry("relative link", "/docs/manifest.yaml", "nested/two.md", "/docs/nested/two.md"),
+		Entry("parent link", "/docs/nested/two.md", "../one.md#title", "/docs/one.md#title"),
+		Entry("file URL source", "file:///docs/manifest.yaml", "./one.md", "file:///docs/one.md"),
+		Entry("anchor", "/docs/one.md", "#title", "/docs/one.md#title"),
+		Entry("absolute path", "/docs/one.md", "/other/two.md", "/other/two.md"),
+		Entry("absolute URL", "/docs/one.md", "https://github.com/gardener/docforge", "https://github.com/gardener/docforge"),
+	)
+})
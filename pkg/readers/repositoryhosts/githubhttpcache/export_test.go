@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package githubhttpcache
+
+import (
+	"context"
+	"time"
+)
+
+func (c *RateLimitCoordinator) SetClock(now func() time.Time, sleep func(ctx context.Context, d time.Duration) error) {
+	c.now, c.sleep = now, sleep
+}
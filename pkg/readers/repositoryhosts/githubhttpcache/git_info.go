@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package githubhttpcache
+
+import (
+	"time"
+
+	"github.com/google/go-github/v43/github"
+)
+
+// MergeGitInfo merges the git info of the sources of an aggregated document. The result has the earliest
+// PublishDate, the latest LastModifiedDate and the author of the earliest published source. The authors of
+// the other sources and all contributors are merged into Contributors, unique by email and in input order.
+// WebURL, SHA, SHAAlias and Path are taken from the first source. Nil infos are skipped, nil is returned if
+// there is none left. Dates in DateFormat are compared in time, other dates lexicographically.
+func MergeGitInfo(infos ...*GitInfo) *GitInfo {
+	var merged *GitInfo
+	var users []*github.User
+	for _, info := range infos {
+		if info == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &GitInfo{
+				WebURL:   info.WebURL,
+				SHA:      info.SHA,
+				SHAAlias: info.SHAAlias,
+				Path:     info.Path,
+			}
+		}
+		if info.PublishDate != nil && (merged.PublishDate == nil || dateBefore(*info.PublishDate, *merged.PublishDate)) {
+			merged.PublishDate = info.PublishDate
+			if info.Author != nil {
+				merged.Author = info.Author
+			}
+		}
+		if info.LastModifiedDate != nil && (merged.LastModifiedDate == nil || dateBefore(*merged.LastModifiedDate, *info.LastModifiedDate)) {
+			merged.LastModifiedDate = info.LastModifiedDate
+		}
+		if info.Author != nil {
+			users = append(users, info.Author)
+		}
+		users = append(users, info.Contributors...)
+	}
+	if merged == nil {
+		return nil
+	}
+	if merged.Author == nil && len(users) > 0 {
+		merged.Author = users[0]
+	}
+	registered := map[string]bool{merged.Author.GetEmail(): true}
+	for _, user := range users {
+		if user == nil || registered[user.GetEmail()] {
+			continue
+		}
+		merged.Contributors = append(merged.Contributors, user)
+		registered[user.GetEmail()] = true
+	}
+	return merged
+}
+
+// dateBefore reports whether the date a is before the date b
+func dateBefore(a, b string) bool {
+	ta, errA := time.Parse(DateFormat, a)
+	tb, errB := time.Parse(DateFormat, b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return ta.Before(tb)
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package githubhttpcache_test
+
+import (
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/githubhttpcache"
+	"github.com/google/go-github/v43/github"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MergeGitInfo", func() {
+	user := func(name string) *github.User {
+		return &github.User{Name: github.String(name), Email: github.String(name + "@example.com")}
+	}
+	It("merges the dates and the users of the sources", func() {
+		one := &githubhttpcache.GitInfo{
+			PublishDate:      github.String("2023-03-01 10:00:00"),
+			LastModifiedDate: github.String("2023-05-01 10:00:00"),
+			Author:           user("alice"),
+			Contributors:     []*github.User{user("bob"), user("carol")},
+			WebURL:           github.String("https://github.com/gardener/docforge"),
+			Path:             github.String("docs/one.md"),
+		}
+		two := &githubhttpcache.GitInfo{
+			PublishDate:      github.String("2022-12-24 08:30:00"),
+			LastModifiedDate: github.String("2023-04-01 10:00:00"),
+			Author:           user("carol"),
+			Contributors:     []*github.User{user("bob"), user("dave"), user("alice")},
+			WebURL:           github.String("https://github.com/gardener/gardener"),
+			Path:             github.String("docs/two.md"),
+		}
+		merged := githubhttpcache.MergeGitInfo(one, nil, two)
+		Expect(*merged.PublishDate).To(Equal("2022-12-24 08:30:00"))
+		Expect(*merged.LastModifiedDate).To(Equal("2023-05-01 10:00:00"))
+		Expect(merged.Author).To(Equal(user("carol")))
+		Expect(merged.Contributors).To(Equal([]*github.User{user("alice"), user("bob"), user("dave")}))
+		Expect(*merged.WebURL).To(Equal("https://github.com/gardener/docforge"))
+		Expect(*merged.Path).To(Equal("docs/one.md"))
+	})
+	It("keeps the fields missing in some sources", func() {
+		one := &githubhttpcache.GitInfo{LastModifiedDate: github.String("2023-05-01 10:00:00")}
+		two := &githubhttpcache.GitInfo{PublishDate: github.String("2023-01-01 10:00:00"), Author: user("alice")}
+		merged := githubhttpcache.MergeGitInfo(one, two)
+		Expect(*merged.PublishDate).To(Equal("2023-01-01 10:00:00"))
+		Expect(*merged.LastModifiedDate).To(Equal("2023-05-01 10:00:00"))
+		Expect(merged.Author).To(Equal(user("alice")))
+		Expect(merged.Contributors).To(BeEmpty())
+	})
+	It("returns nil without sources", func() {
+		Expect(githubhttpcache.MergeGitInfo()).To(BeNil())
+		Expect(githubhttpcache.MergeGitInfo(nil, nil)).To(BeNil())
+	})
+})
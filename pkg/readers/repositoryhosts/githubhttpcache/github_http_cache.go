@@ -10,6 +10,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"net/http"
@@ -17,6 +18,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"sort"
 	"strings"
@@ -48,6 +50,7 @@ type GHC struct {
 	muxDefBr      sync.Mutex
 	muxCnt        sync.Mutex
 	options       manifest.ParsingOptions
+	dateFormat    string
 }
 
 //counterfeiter:generate . RateLimitSource
@@ -62,6 +65,7 @@ type RateLimitSource interface {
 // Repositories is an interface needed for faking
 type Repositories interface {
 	ListCommits(ctx context.Context, owner, repo string, opts *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error)
+	GetCommit(ctx context.Context, owner, repo, sha string, opts *github.ListOptions) (*github.RepositoryCommit, *github.Response, error)
 	GetContents(ctx context.Context, owner, repo, path string, opts *github.RepositoryContentGetOptions) (fileContent *github.RepositoryContent, directoryContent []*github.RepositoryContent, resp *github.Response, err error)
 	Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
 }
@@ -74,8 +78,12 @@ type Git interface {
 	GetTree(ctx context.Context, owner string, repo string, sha string, recursive bool) (*github.Tree, *github.Response, error)
 }
 
-// NewGHC creates new GHC resource handler
-func NewGHC(hostName string, rateLimit RateLimitSource, repositories Repositories, git Git, client httpclient.Client, os osshim.Os, acceptedHosts []string, localMappings map[string]string, options manifest.ParsingOptions) repositoryhosts.RepositoryHost {
+// NewGHC creates new GHC resource handler.
+// dateFormat is the Go time layout of the git info dates, DateFormat is used if it's empty.
+func NewGHC(hostName string, rateLimit RateLimitSource, repositories Repositories, git Git, client httpclient.Client, os osshim.Os, acceptedHosts []string, localMappings map[string]string, options manifest.ParsingOptions, dateFormat string) repositoryhosts.RepositoryHost {
+	if dateFormat == "" {
+		dateFormat = DateFormat
+	}
 	return &GHC{
 		hostName:      hostName,
 		client:        client,
@@ -88,11 +96,12 @@ func NewGHC(hostName string, rateLimit RateLimitSource, repositories Repositorie
 		filesCache:    make(map[string]string),
 		defBranches:   make(map[string]string),
 		options:       options,
+		dateFormat:    dateFormat,
 	}
 }
 
 const (
-	// DateFormat defines format for LastModifiedDate & PublishDate
+	// DateFormat defines the default format for LastModifiedDate & PublishDate
 	DateFormat = "2006-01-02 15:04:05"
 )
 
@@ -108,6 +117,22 @@ type GitInfo struct {
 	Path             *string        `json:"path,omitempty"`
 }
 
+// IsTransient reports whether err is a GitHub API error that may succeed if the request is retried,
+// i.e. a rate limit error or a 429 or 5xx response. Any other error, like a 404, is permanent.
+func IsTransient(err error) bool {
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr) {
+		return true
+	}
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		code := errResp.Response.StatusCode
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}
+	return false
+}
+
 //========================= manifest.FileSource ===================================================
 
 // Tree implements manifest.FileSource#Tree
@@ -120,20 +145,18 @@ func (p *GHC) Tree(resourceURL string) ([]string, error) {
 		return nil, fmt.Errorf("not a tree url: %s", resourceURL)
 	}
 	//bPrefix := fmt.Sprintf("%s://%s/%s/%s/blob/%s/%s", r.URL.Scheme, r.URL.Host, r.Owner, r.Repo, r.Ref, r.Path)
-	p.muxSHA.Lock()
-	defer p.muxSHA.Unlock()
 	local, err := p.checkForLocalMapping(r)
 	if err != nil {
 		return nil, err
 	}
 	if len(local) > 0 {
-		return p.readLocalFileTree(*r, local), nil
+		return p.readLocalFileTree(*r, local)
 	}
 	sha := fmt.Sprintf("%s:%s", r.Ref, r.ResourcePath)
 	sha = url.PathEscape(sha)
 	tree, resp, err := p.git.GetTree(context.TODO(), r.Owner, r.Repo, sha, true)
 	if resp != nil && resp.StatusCode == http.StatusNotFound {
-		return nil, repositoryhosts.ErrResourceNotFound(resourceURL)
+		return nil, repositoryhosts.NotFound(resourceURL, err)
 	}
 	if resp != nil && resp.StatusCode >= 400 {
 		return nil, fmt.Errorf("reading tree %s fails with HTTP status: %d", resourceURL, resp.StatusCode)
@@ -142,17 +165,26 @@ func (p *GHC) Tree(resourceURL string) ([]string, error) {
 		return nil, err
 	}
 	res := []string{}
+	// the SHAs cache is locked only to update it, trees are fetched concurrently
+	p.muxSHA.Lock()
+	defer p.muxSHA.Unlock()
 	for _, e := range tree.Entries {
 		extracted := false
 		ePath := strings.TrimPrefix(*e.Path, "/")
+		// cache the SHAs of the tree blobs to read them with a single request each
+		if *e.Type == "blob" && e.SHA != nil {
+			if blobURL, err := repositoryhosts.TreeFileURL(resourceURL, ePath); err == nil {
+				p.filesCache[blobURL] = *e.SHA
+			}
+		}
 		for _, extractedFormat := range p.options.ExtractedFilesFormats {
 			if strings.HasSuffix(strings.ToLower(ePath), extractedFormat) {
 				extracted = true
 				break
 			}
 		}
-		// skip node if it is not a supported format
-		if *e.Type != "blob" || !extracted {
+		// skip node if it is not a supported format or an ignore file
+		if *e.Type != "blob" || !extracted && path.Base(ePath) != repositoryhosts.IgnoreFileName {
 			//klog.V(6).Infof("node selector %s skip entry %s\n", node.NodeSelector.Path, ePath)
 			continue
 		}
@@ -161,6 +193,39 @@ func (p *GHC) Tree(resourceURL string) ([]string, error) {
 	return res, nil
 }
 
+// List implements repositoryhosts.Lister#List
+func (p *GHC) List(ctx context.Context, resourceURL string) ([]repositoryhosts.ResourceEntry, error) {
+	r, err := p.resolveDefaultBranch(ctx, resourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not list directory: %w", err)
+	}
+	if r.Type != "tree" {
+		return nil, fmt.Errorf("not a tree url: %s", resourceURL)
+	}
+	local, err := p.checkForLocalMapping(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(local) > 0 {
+		return p.listLocalDir(r, local)
+	}
+	dc, resp, err := p.getDirContents(ctx, r.Owner, r.Repo, r.ResourcePath, &github.RepositoryContentGetOptions{Ref: r.Ref})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, repositoryhosts.NotFound(resourceURL, err)
+		}
+		return nil, err
+	}
+	if resp != nil && resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("listing directory %s fails with HTTP status: %d", resourceURL, resp.StatusCode)
+	}
+	entries := make([]repositoryhosts.ResourceEntry, 0, len(dc))
+	for _, d := range dc {
+		entries = append(entries, repositoryhosts.ResourceEntry{Name: d.GetName(), IsDir: d.GetType() == "dir", Size: int64(d.GetSize())})
+	}
+	return entries, nil
+}
+
 // ToAbsLink implements manifest.FileSource#ToAbsLink
 func (p *GHC) ToAbsLink(source, link string) (string, error) {
 	r, err := p.resolveDefaultBranch(context.TODO(), source)
@@ -258,7 +323,7 @@ func (p *GHC) Read(ctx context.Context, resourceURL string) ([]byte, error) {
 		raw, resp, err := p.git.GetBlobRaw(ctx, r.Owner, r.Repo, SHA)
 		if err != nil {
 			if resp != nil && resp.StatusCode == http.StatusNotFound {
-				return nil, repositoryhosts.ErrResourceNotFound(resourceURL)
+				return nil, repositoryhosts.NotFound(resourceURL, err)
 			}
 			return nil, err
 		}
@@ -276,7 +341,7 @@ func (p *GHC) Read(ctx context.Context, resourceURL string) ([]byte, error) {
 	fc, _, resp, err := p.repositories.GetContents(ctx, r.Owner, r.Repo, r.ResourcePath, opt)
 	if err != nil {
 		if resp != nil && resp.StatusCode == http.StatusNotFound {
-			return nil, repositoryhosts.ErrResourceNotFound(resourceURL)
+			return nil, repositoryhosts.NotFound(resourceURL, err)
 		}
 		if resp != nil && resp.StatusCode == http.StatusForbidden {
 			// if file is bigger than 1 MB -> content should be downloaded
@@ -313,7 +378,7 @@ func (p *GHC) ReadGitInfo(ctx context.Context, resourceURL string) ([]byte, erro
 	if resp != nil && resp.StatusCode >= 400 {
 		return nil, fmt.Errorf("list commits for %s fails with HTTP status: %d", r.String(), resp.StatusCode)
 	}
-	gitInfo := transform(commits)
+	gitInfo := transform(commits, p.dateFormat)
 	if gitInfo == nil {
 		return nil, nil
 	}
@@ -326,6 +391,52 @@ func (p *GHC) ReadGitInfo(ctx context.Context, resourceURL string) ([]byte, erro
 	return json.MarshalIndent(gitInfo, "", "  ")
 }
 
+// MovedTo implements repositoryhosts.MoveTracker#MovedTo by looking up the renames in the last commit of the resource path
+func (p *GHC) MovedTo(ctx context.Context, resourceURL string) (string, error) {
+	r, err := p.resolveDefaultBranch(ctx, resourceURL)
+	if err != nil {
+		return "", err
+	}
+	opts := &github.CommitsListOptions{
+		Path:        r.ResourcePath,
+		SHA:         r.Ref,
+		ListOptions: github.ListOptions{PerPage: 1},
+	}
+	commits, resp, err := p.repositories.ListCommits(ctx, r.Owner, r.Repo, opts)
+	if err != nil {
+		return "", err
+	}
+	if resp != nil && resp.StatusCode >= 400 {
+		return "", fmt.Errorf("list commits for %s fails with HTTP status: %d", r.String(), resp.StatusCode)
+	}
+	if len(commits) == 0 {
+		return "", nil
+	}
+	commit, resp, err := p.repositories.GetCommit(ctx, r.Owner, r.Repo, commits[0].GetSHA(), nil)
+	if err != nil {
+		return "", err
+	}
+	if resp != nil && resp.StatusCode >= 400 {
+		return "", fmt.Errorf("get commit %s fails with HTTP status: %d", commits[0].GetSHA(), resp.StatusCode)
+	}
+	dirPrefix := strings.TrimSuffix(r.ResourcePath, "/") + "/"
+	for _, f := range commit.Files {
+		if f.GetStatus() != "renamed" {
+			continue
+		}
+		if f.GetPreviousFilename() == r.ResourcePath {
+			r.ResourcePath = f.GetFilename()
+			return r.String(), nil
+		}
+		// a file of a renamed directory
+		if suffix := strings.TrimPrefix(f.GetPreviousFilename(), dirPrefix); suffix != f.GetPreviousFilename() && strings.HasSuffix(f.GetFilename(), "/"+suffix) {
+			r.ResourcePath = strings.TrimSuffix(f.GetFilename(), "/"+suffix)
+			return r.String(), nil
+		}
+	}
+	return "", nil
+}
+
 // GetRawFormatLink implements the repositoryhosts.RepositoryHost#GetRawFormatLink
 func (p *GHC) GetRawFormatLink(link string) (string, error) {
 	url, err := url.Parse(link)
@@ -370,29 +481,75 @@ func (p *GHC) checkForLocalMapping(r *resource.URL) (string, error) {
 	return p.localMappings[key+"/"], nil
 }
 
+// localResourcePath joins the resource path to the locally mapped repository root and
+// rejects resource paths resolving outside of it, e.g. `../../etc`
+func localResourcePath(r *resource.URL, localPath string) (string, error) {
+	root := filepath.Clean(localPath)
+	resourcePath := filepath.Join(root, filepath.FromSlash(r.ResourcePath))
+	rel, err := filepath.Rel(root, resourcePath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("resource path %s of uri %s is outside of the local repository %s", r.ResourcePath, r.String(), localPath)
+	}
+	return resourcePath, nil
+}
+
 // readLocalFile reads a file from FS
 func (p *GHC) readLocalFile(_ context.Context, r *resource.URL, localPath string) ([]byte, error) {
-	fn := filepath.Join(localPath, r.ResourcePath)
+	fn, err := localResourcePath(r, localPath)
+	if err != nil {
+		return nil, err
+	}
 	cnt, err := p.os.ReadFile(fn)
 	if err != nil {
 		if p.os.IsNotExist(err) {
-			return nil, repositoryhosts.ErrResourceNotFound(r.String())
+			return nil, repositoryhosts.NotFound(r.String(), err)
 		}
 		return nil, fmt.Errorf("reading file %s for uri %s fails: %v", fn, r.String(), err)
 	}
 	return cnt, nil
 }
 
-func (p *GHC) readLocalFileTree(r resource.URL, localPath string) []string {
-	dirPath := filepath.Join(localPath, r.ResourcePath)
+func (p *GHC) readLocalFileTree(r resource.URL, localPath string) ([]string, error) {
+	dirPath, err := localResourcePath(&r, localPath)
+	if err != nil {
+		return nil, err
+	}
 	files := []string{}
 	filepath.Walk(dirPath, func(path string, info fs.FileInfo, err error) error {
-		if !info.IsDir() && strings.HasSuffix(path, ".md") {
+		if !info.IsDir() && (strings.HasSuffix(path, ".md") || filepath.Base(path) == repositoryhosts.IgnoreFileName) {
 			files = append(files, strings.TrimPrefix(strings.TrimPrefix(path, dirPath), "/"))
 		}
 		return nil
 	})
-	return files
+	return files, nil
+}
+
+// listLocalDir lists a directory from FS
+func (p *GHC) listLocalDir(r *resource.URL, localPath string) ([]repositoryhosts.ResourceEntry, error) {
+	dirPath, err := localResourcePath(r, localPath)
+	if err != nil {
+		return nil, err
+	}
+	des, err := os.ReadDir(dirPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, repositoryhosts.NotFound(r.String(), err)
+		}
+		return nil, fmt.Errorf("listing directory %s for uri %s fails: %v", dirPath, r.String(), err)
+	}
+	entries := make([]repositoryhosts.ResourceEntry, 0, len(des))
+	for _, de := range des {
+		info, err := de.Info()
+		if err != nil {
+			return nil, fmt.Errorf("listing directory %s for uri %s fails: %v", dirPath, r.String(), err)
+		}
+		entry := repositoryhosts.ResourceEntry{Name: de.Name(), IsDir: de.IsDir()}
+		if !entry.IsDir {
+			entry.Size = info.Size()
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
 }
 
 // downloadContent download file content like: github.Client.Repositories#DownloadContents, but with different error handling
@@ -402,10 +559,18 @@ func (p *GHC) downloadContent(ctx context.Context, opt *github.RepositoryContent
 	dirContents, resp, err := p.getDirContents(ctx, r.Owner, r.Repo, dir, opt)
 	if err != nil {
 		if resp != nil && resp.StatusCode == http.StatusNotFound {
-			return nil, repositoryhosts.ErrResourceNotFound(r.String())
+			return nil, repositoryhosts.NotFound(r.String(), err)
 		}
 		return nil, err
 	}
+	// cache the SHAs of the directory files to read them without listing the directory again
+	p.muxSHA.Lock()
+	for _, contents := range dirContents {
+		if contents.GetType() == "file" && contents.GetHTMLURL() != "" && contents.GetSHA() != "" {
+			p.filesCache[contents.GetHTMLURL()] = contents.GetSHA()
+		}
+	}
+	p.muxSHA.Unlock()
 	for _, contents := range dirContents {
 		if *contents.Name == filename {
 			if contents.SHA == nil || *contents.SHA == "" {
@@ -414,7 +579,7 @@ func (p *GHC) downloadContent(ctx context.Context, opt *github.RepositoryContent
 			cnt, resp, err := p.git.GetBlobRaw(ctx, r.Owner, r.Repo, *contents.SHA)
 			if err != nil {
 				if resp != nil && resp.StatusCode == http.StatusNotFound {
-					return nil, repositoryhosts.ErrResourceNotFound(r.String())
+					return nil, repositoryhosts.NotFound(r.String(), err)
 				}
 				return nil, err
 			}
@@ -544,6 +709,11 @@ func (p *GHC) getDefaultBranch(ctx context.Context, owner string, repository str
 	return def, nil
 }
 
+// BlobSHA implements the repositoryhosts.BlobHasher#BlobSHA, the SHAs of the files listed by Tree are known
+func (p *GHC) BlobSHA(resourceURL string) (string, bool) {
+	return p.getFileSHA(resourceURL)
+}
+
 func (p *GHC) getFileSHA(key string) (string, bool) {
 	p.muxSHA.RLock()
 	defer p.muxSHA.RUnlock()
@@ -552,7 +722,7 @@ func (p *GHC) getFileSHA(key string) (string, bool) {
 }
 
 // transform builds git.Info from a commits list
-func transform(commits []*github.RepositoryCommit) *GitInfo {
+func transform(commits []*github.RepositoryCommit, dateFormat string) *GitInfo {
 	if commits == nil {
 		return nil
 	}
@@ -565,35 +735,48 @@ func transform(commits []*github.RepositoryCommit) *GitInfo {
 	sort.Slice(nonInternalCommits, func(i, j int) bool {
 		return nonInternalCommits[i].GetCommit().GetCommitter().GetDate().After(nonInternalCommits[j].GetCommit().GetCommitter().GetDate())
 	})
-	lastModifiedDate := nonInternalCommits[0].GetCommit().GetCommitter().GetDate().Format(DateFormat)
+	lastModifiedDate := nonInternalCommits[0].GetCommit().GetCommitter().GetDate().Format(dateFormat)
 	gitInfo.LastModifiedDate = &lastModifiedDate
 
 	webURL := nonInternalCommits[0].GetHTMLURL()
 	gitInfo.WebURL = github.String(strings.Split(webURL, "/commit/")[0])
 
-	gitInfo.PublishDate = github.String(nonInternalCommits[len(nonInternalCommits)-1].GetCommit().GetCommitter().GetDate().Format(DateFormat))
+	gitInfo.PublishDate = github.String(nonInternalCommits[len(nonInternalCommits)-1].GetCommit().GetCommitter().GetDate().Format(dateFormat))
 
 	if gitInfo.Author = getCommitAuthor(nonInternalCommits[len(nonInternalCommits)-1]); gitInfo.Author == nil {
 		klog.Warningf("cannot get commit author")
 	}
-	if len(nonInternalCommits) < 2 {
-		return gitInfo
-	}
-	gitInfo.Contributors = []*github.User{}
 	var registered []string
 	for _, commit := range nonInternalCommits {
-		var contributor *github.User
-		if contributor = getCommitAuthor(commit); contributor == nil {
-			continue
-		}
-		if contributor.GetType() == "User" && contributor.GetEmail() != gitInfo.Author.GetEmail() && slices.Index(registered, contributor.GetEmail()) < 0 {
-			gitInfo.Contributors = append(gitInfo.Contributors, contributor)
-			registered = append(registered, contributor.GetEmail())
+		for _, contributor := range getCommitContributors(commit) {
+			if contributor.GetType() == "User" && contributor.GetEmail() != gitInfo.Author.GetEmail() && slices.Index(registered, contributor.GetEmail()) < 0 {
+				gitInfo.Contributors = append(gitInfo.Contributors, contributor)
+				registered = append(registered, contributor.GetEmail())
+			}
 		}
 	}
 	return gitInfo
 }
 
+// coAuthoredBy matches a `Co-authored-by: Name <email>` commit message trailer
+var coAuthoredBy = regexp.MustCompile(`(?im)^\s*co-authored-by:\s*(.*?)\s*<([^>\s]+)>\s*$`)
+
+// getCommitContributors returns the commit author followed by the co-authors from the commit message trailers
+func getCommitContributors(commit *github.RepositoryCommit) []*github.User {
+	var contributors []*github.User
+	if author := getCommitAuthor(commit); author != nil {
+		contributors = append(contributors, author)
+	}
+	for _, m := range coAuthoredBy.FindAllStringSubmatch(commit.GetCommit().GetMessage(), -1) {
+		contributors = append(contributors, &github.User{
+			Name:  github.String(m[1]),
+			Email: github.String(m[2]),
+			Type:  github.String("User"),
+		})
+	}
+	return contributors
+}
+
 func isInternalCommit(commit *github.RepositoryCommit) bool {
 	message := commit.GetCommit().GetMessage()
 	email := commit.GetCommitter().GetEmail()
@@ -7,8 +7,17 @@ package githubhttpcache_test
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io/fs"
 	"net/http"
+	"net/http/httptest"
+	goos "os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -36,16 +45,20 @@ var _ = Describe("Github cache test", func() {
 		git          githubhttpcachefakes.FakeGit
 		client       httpclient.Client
 		os           osshim.Os
+		dateFormat   string
+		mappings     map[string]string
 	)
 
 	BeforeEach(func() {
+		dateFormat = ""
+		mappings = map[string]string{}
 		rls = githubhttpcachefakes.FakeRateLimitSource{}
 		repositories = githubhttpcachefakes.FakeRepositories{}
 		git = githubhttpcachefakes.FakeGit{}
 	})
 
 	JustBeforeEach(func() {
-		ghc = githubhttpcache.NewGHC("testing", &rls, &repositories, &git, client, os, []string{"github.com"}, map[string]string{}, manifest.ParsingOptions{ExtractedFilesFormats: []string{".md"}, Hugo: true})
+		ghc = githubhttpcache.NewGHC("testing", &rls, &repositories, &git, client, os, []string{"github.com"}, mappings, manifest.ParsingOptions{ExtractedFilesFormats: []string{".md"}, Hugo: true}, dateFormat)
 	})
 
 	Describe("#GetRateLimit", func() {
@@ -101,6 +114,12 @@ var _ = Describe("Github cache test", func() {
 						{
 							Path: github.String("/Makefile"),
 							Type: github.String("blob"),
+							SHA:  github.String("fe6b1a8"),
+						},
+						{
+							Path: github.String("/release notes.md"),
+							Type: github.String("blob"),
+							SHA:  github.String("a1b2c3d"),
 						},
 						{
 							Path: github.String("/pkg"),
@@ -114,6 +133,10 @@ var _ = Describe("Github cache test", func() {
 							Path: github.String("/docs/_index.md"),
 							Type: github.String("blob"),
 						},
+						{
+							Path: github.String("/docs/.docforgeignore"),
+							Type: github.String("blob"),
+						},
 					},
 				}
 				git.GetTreeReturns(&tree, nil, nil)
@@ -121,12 +144,128 @@ var _ = Describe("Github cache test", func() {
 
 			It("not found", func() {
 				tree, err := ghc.Tree("https://github.com/gardener/docforge/tree/master/pkg")
-				Expect(tree).To(Equal([]string{"README.md", "docs/_index.md"}))
+				Expect(tree).To(Equal([]string{"README.md", "release notes.md", "docs/_index.md", "docs/.docforgeignore"}))
 				Expect(err).NotTo(HaveOccurred())
 
 			})
+			It("knows the blob SHAs of the tree files", func() {
+				_, err := ghc.Tree("https://github.com/gardener/docforge/tree/master/pkg")
+				Expect(err).NotTo(HaveOccurred())
+				hasher, ok := ghc.(repositoryhosts.BlobHasher)
+				Expect(ok).To(BeTrue())
+				sha, ok := hasher.BlobSHA("https://github.com/gardener/docforge/blob/master/pkg/Makefile")
+				Expect(ok).To(BeTrue())
+				Expect(sha).To(Equal("fe6b1a8"))
+				_, ok = hasher.BlobSHA("https://github.com/gardener/docforge/blob/master/pkg/README.md")
+				Expect(ok).To(BeFalse())
+				// the node sources of the tree files are not escaped
+				sha, ok = hasher.BlobSHA("https://github.com/gardener/docforge/blob/master/pkg/release notes.md")
+				Expect(ok).To(BeTrue())
+				Expect(sha).To(Equal("a1b2c3d"))
+			})
 		})
 
+		Describe("reading trees concurrently", func() {
+			var started, release chan struct{}
+			BeforeEach(func() {
+				started = make(chan struct{}, 2)
+				release = make(chan struct{})
+				git.GetTreeCalls(func(_ context.Context, _ string, _ string, _ string, _ bool) (*github.Tree, *github.Response, error) {
+					started <- struct{}{}
+					<-release
+					return &github.Tree{}, nil, nil
+				})
+			})
+			It("fetches the trees in parallel", func() {
+				errs := make(chan error, 2)
+				for _, dir := range []string{"pkg", "docs"} {
+					go func(dir string) {
+						_, err := ghc.Tree("https://github.com/gardener/docforge/tree/master/" + dir)
+						errs <- err
+					}(dir)
+				}
+				Eventually(started).Should(Receive())
+				Eventually(started).Should(Receive())
+				close(release)
+				Eventually(errs).Should(Receive(BeNil()))
+				Eventually(errs).Should(Receive(BeNil()))
+			})
+		})
+		Describe("locally mapped directory", func() {
+			BeforeEach(func() {
+				mappings["https://github.com/gardener/docforge"] = localRepository()
+			})
+			AfterEach(func() {
+				Expect(goos.RemoveAll(mappings["https://github.com/gardener/docforge"])).To(Succeed())
+			})
+			It("walks paths inside of the local repository", func() {
+				tree, err := ghc.Tree("https://github.com/gardener/docforge/tree/master/docs/guides/../")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tree).To(ConsistOf("readme.md", "guides/setup.md"))
+				Expect(git.GetTreeCallCount()).To(Equal(0))
+			})
+			It("rejects paths outside of the local repository", func() {
+				_, err := ghc.Tree("https://github.com/gardener/docforge/tree/master/docs/../../../etc")
+				Expect(err).To(MatchError(ContainSubstring("is outside of the local repository")))
+			})
+		})
+
+	})
+
+	Describe("#List", func() {
+		var lister repositoryhosts.Lister
+		JustBeforeEach(func() {
+			lister = ghc.(repositoryhosts.Lister)
+		})
+		It("not a tree url", func() {
+			_, err := lister.List(context.TODO(), "https://github.com/gardener/docforge/blob/master/README.md")
+			Expect(err).To(MatchError(ContainSubstring("not a tree url")))
+		})
+		Describe("remote directory", func() {
+			BeforeEach(func() {
+				repositories.GetContentsReturns(nil, []*github.RepositoryContent{
+					{Name: github.String("README.md"), Type: github.String("file"), Size: github.Int(42)},
+					{Name: github.String("docs"), Type: github.String("dir")},
+				}, nil, nil)
+			})
+			It("lists the entries", func() {
+				entries, err := lister.List(context.TODO(), "https://github.com/gardener/docforge/tree/master/pkg")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(Equal([]repositoryhosts.ResourceEntry{
+					{Name: "README.md", Size: 42},
+					{Name: "docs", IsDir: true},
+				}))
+				_, _, _, path, opts := repositories.GetContentsArgsForCall(0)
+				Expect(path).To(Equal("pkg"))
+				Expect(opts.Ref).To(Equal("master"))
+			})
+		})
+		Describe("locally mapped directory", func() {
+			BeforeEach(func() {
+				mappings["https://github.com/gardener/docforge"] = localRepository()
+			})
+			AfterEach(func() {
+				Expect(goos.RemoveAll(mappings["https://github.com/gardener/docforge"])).To(Succeed())
+			})
+			It("lists the entries", func() {
+				entries, err := lister.List(context.TODO(), "https://github.com/gardener/docforge/tree/master/docs")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(Equal([]repositoryhosts.ResourceEntry{
+					{Name: "guides", IsDir: true},
+					{Name: "readme.md", Size: 7},
+				}))
+				Expect(repositories.GetContentsCallCount()).To(Equal(0))
+			})
+			It("returns not found for missing directories", func() {
+				_, err := lister.List(context.TODO(), "https://github.com/gardener/docforge/tree/master/missing")
+				Expect(errors.Is(err, repositoryhosts.ErrNotFound)).To(BeTrue())
+				Expect(errors.Is(err, fs.ErrNotExist)).To(BeTrue())
+			})
+			It("rejects paths outside of the local repository", func() {
+				_, err := lister.List(context.TODO(), "https://github.com/gardener/docforge/tree/master/docs/../../")
+				Expect(err).To(MatchError(ContainSubstring("is outside of the local repository")))
+			})
+		})
 	})
 
 	Describe("#ToAbsLink", func() {
@@ -211,14 +350,107 @@ var _ = Describe("Github cache test", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(string(content)).To(Equal("logo_contents"))
 			})
+			It("lists the directory once for sibling files", func() {
+				docsContent := []*github.RepositoryContent{
+					{Name: github.String("logo.png"), Type: github.String("file"), SHA: github.String("321"), HTMLURL: github.String("https://github.com/gardener/docforge/blob/master/logo.png")},
+					{Name: github.String("icon.png"), Type: github.String("file"), SHA: github.String("654"), HTMLURL: github.String("https://github.com/gardener/docforge/blob/master/icon.png")},
+				}
+				repositories.GetContentsReturns(nil, docsContent, nil, nil)
+				for _, file := range []string{"logo.png", "icon.png"} {
+					_, err := ghc.Read(context.TODO(), "https://github.com/gardener/docforge/blob/master/"+file)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(repositories.GetContentsCallCount()).To(Equal(1))
+				Expect(git.GetBlobRawCallCount()).To(Equal(2))
+				_, _, _, sha := git.GetBlobRawArgsForCall(1)
+				Expect(sha).To(Equal("654"))
+			})
+		})
+		Describe("files of a tree", func() {
+			const n = 5
+			BeforeEach(func() {
+				tree := &github.Tree{}
+				for i := 0; i < n; i++ {
+					tree.Entries = append(tree.Entries, &github.TreeEntry{
+						Path: github.String(fmt.Sprintf("/docs/file%d.md", i)),
+						Type: github.String("blob"),
+						SHA:  github.String(fmt.Sprintf("sha%d", i)),
+					})
+				}
+				git.GetTreeReturns(tree, nil, nil)
+				git.GetBlobRawCalls(func(_ context.Context, _ string, _ string, sha string) ([]byte, *github.Response, error) {
+					return []byte(sha), nil, nil
+				})
+			})
+			It("are read with the tree blob SHAs", func() {
+				files, err := ghc.Tree("https://github.com/gardener/docforge/tree/master/pkg")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(HaveLen(n))
+				for i, file := range files {
+					content, err := ghc.Read(context.TODO(), "https://github.com/gardener/docforge/blob/master/pkg/"+file)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(content)).To(Equal(fmt.Sprintf("sha%d", i)))
+				}
+				Expect(git.GetTreeCallCount() + git.GetBlobRawCallCount() + repositories.GetContentsCallCount()).To(Equal(n + 1))
+			})
+		})
+	})
+
+	Describe("#IsTransient", func() {
+		var statusErr = func(code int) error {
+			resp := &http.Response{StatusCode: code, Request: &http.Request{Method: http.MethodGet}}
+			return &github.ErrorResponse{Response: resp}
+		}
+		It("classifies 5xx and 429 responses as transient", func() {
+			Expect(githubhttpcache.IsTransient(statusErr(http.StatusBadGateway))).To(BeTrue())
+			Expect(githubhttpcache.IsTransient(statusErr(http.StatusTooManyRequests))).To(BeTrue())
+			Expect(githubhttpcache.IsTransient(&github.RateLimitError{})).To(BeTrue())
+		})
+		It("classifies other errors as permanent", func() {
+			Expect(githubhttpcache.IsTransient(statusErr(http.StatusNotFound))).To(BeFalse())
+			Expect(githubhttpcache.IsTransient(statusErr(http.StatusUnauthorized))).To(BeFalse())
+			Expect(githubhttpcache.IsTransient(errors.New("yataa error"))).To(BeFalse())
+		})
+		Describe("reading fails", func() {
+			var code int
+			JustBeforeEach(func() {
+				resp := &github.Response{Response: &http.Response{StatusCode: code}}
+				repositories.GetContentsReturns(nil, nil, resp, statusErr(code))
+			})
+			Context("with 502", func() {
+				BeforeEach(func() {
+					code = http.StatusBadGateway
+				})
+				It("returns a transient error", func() {
+					_, err := ghc.Read(context.TODO(), "https://github.com/gardener/docforge/blob/master/README.md")
+					Expect(err).To(HaveOccurred())
+					Expect(githubhttpcache.IsTransient(err)).To(BeTrue())
+				})
+			})
+			Context("with 404", func() {
+				BeforeEach(func() {
+					code = http.StatusNotFound
+				})
+				It("returns a permanent error", func() {
+					_, err := ghc.Read(context.TODO(), "https://github.com/gardener/docforge/blob/master/README.md")
+					Expect(errors.Is(err, repositoryhosts.ErrNotFound)).To(BeTrue())
+					var notFound repositoryhosts.ErrResourceNotFound
+					Expect(errors.As(err, &notFound)).To(BeTrue())
+					Expect(notFound).To(Equal(repositoryhosts.ErrResourceNotFound("https://github.com/gardener/docforge/blob/master/README.md")))
+					var errResp *github.ErrorResponse
+					Expect(errors.As(err, &errResp)).To(BeTrue())
+					Expect(githubhttpcache.IsTransient(err)).To(BeFalse())
+				})
+			})
 		})
 	})
 
 	Describe("#ReadGitInfo", func() {
+		var commits []*github.RepositoryCommit
 		BeforeEach(func() {
 			time1 := time.Date(2024, time.February, 6, 13, 11, 0, 0, time.UTC)
 			time2 := time.Date(2024, time.February, 7, 13, 11, 0, 0, time.UTC)
-			commits := []*github.RepositoryCommit{
+			commits = []*github.RepositoryCommit{
 				{
 					Author: &github.User{
 						Name:  github.String("one"),
@@ -251,6 +483,191 @@ var _ = Describe("Github cache test", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(content)).To(Equal("{\n  \"lastmod\": \"2024-02-07 13:11:00\",\n  \"publishdate\": \"2024-02-06 13:11:00\",\n  \"author\": {\n    \"name\": \"one\",\n    \"email\": \"one@\"\n  },\n  \"weburl\": \"bar\",\n  \"shaalias\": \"master\",\n  \"path\": \"README.md\"\n}"))
 		})
+
+		Context("date format is configured", func() {
+			BeforeEach(func() {
+				dateFormat = time.RFC3339
+			})
+			It("formats the dates with it", func() {
+				content, err := ghc.ReadGitInfo(context.TODO(), "https://github.com/gardener/docforge/blob/master/README.md")
+				Expect(err).NotTo(HaveOccurred())
+				gitInfo := &githubhttpcache.GitInfo{}
+				Expect(json.Unmarshal(content, gitInfo)).To(Succeed())
+				Expect(*gitInfo.LastModifiedDate).To(Equal("2024-02-07T13:11:00Z"))
+				Expect(*gitInfo.PublishDate).To(Equal("2024-02-06T13:11:00Z"))
+			})
+		})
+
+		It("adds co-authors as contributors", func() {
+			commits[1].Commit.Message = github.String("Update README\n\nCo-authored-by: Two <two@>\nco-authored-by: one <one@>\nCo-authored-by: Three Four <three@>")
+			content, err := ghc.ReadGitInfo(context.TODO(), "https://github.com/gardener/docforge/blob/master/README.md")
+			Expect(err).NotTo(HaveOccurred())
+			gitInfo := &githubhttpcache.GitInfo{}
+			Expect(json.Unmarshal(content, gitInfo)).To(Succeed())
+			Expect(gitInfo.Contributors).To(Equal([]*github.User{
+				{Name: github.String("Two"), Email: github.String("two@"), Type: github.String("User")},
+				{Name: github.String("Three Four"), Email: github.String("three@"), Type: github.String("User")},
+			}))
+		})
+	})
+
+	Describe("#MovedTo", func() {
+		var files []*github.CommitFile
+		BeforeEach(func() {
+			repositories.ListCommitsReturns([]*github.RepositoryCommit{{SHA: github.String("abc")}}, nil, nil)
+			files = []*github.CommitFile{
+				{Filename: github.String("docs/other.md"), Status: github.String("modified")},
+				{Filename: github.String("docs/new.md"), PreviousFilename: github.String("docs/old.md"), Status: github.String("renamed")},
+				{Filename: github.String("website/guides/setup.md"), PreviousFilename: github.String("docs/guides/setup.md"), Status: github.String("renamed")},
+			}
+		})
+		JustBeforeEach(func() {
+			repositories.GetCommitReturns(&github.RepositoryCommit{SHA: github.String("abc"), Files: files}, nil, nil)
+		})
+		It("returns the URL of a renamed file", func() {
+			moved, err := ghc.(repositoryhosts.MoveTracker).MovedTo(context.TODO(), "https://github.com/gardener/docforge/blob/master/docs/old.md")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(moved).To(Equal("https://github.com/gardener/docforge/blob/master/docs/new.md"))
+			_, owner, repo, opts := repositories.ListCommitsArgsForCall(0)
+			Expect([]string{owner, repo, opts.Path, opts.SHA}).To(Equal([]string{"gardener", "docforge", "docs/old.md", "master"}))
+			_, _, _, sha, _ := repositories.GetCommitArgsForCall(0)
+			Expect(sha).To(Equal("abc"))
+		})
+		It("returns the URL of a renamed directory", func() {
+			moved, err := ghc.(repositoryhosts.MoveTracker).MovedTo(context.TODO(), "https://github.com/gardener/docforge/tree/master/docs/guides")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(moved).To(Equal("https://github.com/gardener/docforge/tree/master/website/guides"))
+		})
+		It("returns empty string if the resource was not renamed", func() {
+			moved, err := ghc.(repositoryhosts.MoveTracker).MovedTo(context.TODO(), "https://github.com/gardener/docforge/blob/master/docs/other.md")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(moved).To(BeEmpty())
+		})
+		It("returns empty string if the resource has no history", func() {
+			repositories.ListCommitsReturns(nil, nil, nil)
+			moved, err := ghc.(repositoryhosts.MoveTracker).MovedTo(context.TODO(), "https://github.com/gardener/docforge/blob/master/docs/old.md")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(moved).To(BeEmpty())
+			Expect(repositories.GetCommitCallCount()).To(Equal(0))
+		})
 	})
 
 })
+
+var _ = Describe("RateLimitCoordinator", func() {
+	var (
+		server      *httptest.Server
+		coordinator *githubhttpcache.RateLimitCoordinator
+		client      *http.Client
+		now         time.Time
+		reset       time.Time
+		remaining   int32
+		pauses      []time.Duration
+		mux         sync.Mutex
+	)
+	BeforeEach(func() {
+		now = time.Now()
+		reset = now.Add(time.Hour).Truncate(time.Second)
+		remaining = 15
+		pauses = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			left := atomic.AddInt32(&remaining, -1)
+			mux.Lock()
+			defer mux.Unlock()
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(int(left)))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		}))
+		coordinator = githubhttpcache.NewRateLimitCoordinator(http.DefaultTransport, 10)
+		coordinator.SetClock(func() time.Time {
+			mux.Lock()
+			defer mux.Unlock()
+			return now
+		}, func(ctx context.Context, d time.Duration) error {
+			mux.Lock()
+			defer mux.Unlock()
+			pauses = append(pauses, d)
+			return nil
+		})
+		client = &http.Client{Transport: coordinator}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	get := func() {
+		resp, err := client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+	}
+	It("pauses until the reset when the remaining requests reach the reserve", func() {
+		for i := 0; i < 5; i++ {
+			get()
+		}
+		Expect(atomic.LoadInt32(&remaining)).To(Equal(int32(10)))
+		Expect(pauses).To(BeEmpty())
+		get()
+		Expect(pauses).To(Equal([]time.Duration{reset.Sub(now)}))
+	})
+	It("resumes without pausing after the reset", func() {
+		for i := 0; i < 6; i++ {
+			get()
+		}
+		Expect(pauses).To(HaveLen(1))
+		mux.Lock()
+		now = reset
+		reset = reset.Add(time.Hour)
+		mux.Unlock()
+		atomic.StoreInt32(&remaining, 5000)
+		get()
+		get()
+		Expect(pauses).To(HaveLen(1))
+	})
+	It("pauses all workers sharing the client", func() {
+		for i := 0; i < 5; i++ {
+			get()
+		}
+		wg := sync.WaitGroup{}
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				get()
+			}()
+		}
+		wg.Wait()
+		Expect(pauses).To(HaveLen(4))
+	})
+	It("doesn't pause without rate limit headers", func() {
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer plain.Close()
+		for i := 0; i < 20; i++ {
+			resp, err := client.Get(plain.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Body.Close()).To(Succeed())
+		}
+		Expect(pauses).To(BeEmpty())
+	})
+	It("stops pausing when the request is canceled", func() {
+		coordinator.SetClock(func() time.Time { return now }, nil)
+		for i := 0; i < 5; i++ {
+			get()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Do(req)
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		Expect(atomic.LoadInt32(&remaining)).To(Equal(int32(10)))
+	})
+})
+
+// localRepository creates a temporary repository directory with docs/readme.md and docs/guides/setup.md
+func localRepository() string {
+	dir, err := goos.MkdirTemp("", "docforge")
+	Expect(err).NotTo(HaveOccurred())
+	Expect(goos.MkdirAll(filepath.Join(dir, "docs", "guides"), 0755)).To(Succeed())
+	Expect(goos.WriteFile(filepath.Join(dir, "docs", "readme.md"), []byte("# Guide"), 0644)).To(Succeed())
+	Expect(goos.WriteFile(filepath.Join(dir, "docs", "guides", "setup.md"), []byte("# Setup"), 0644)).To(Succeed())
+	return dir
+}
@@ -30,6 +30,25 @@ type FakeRepositories struct {
 		result2 *github.Response
 		result3 error
 	}
+	GetCommitStub        func(context.Context, string, string, string, *github.ListOptions) (*github.RepositoryCommit, *github.Response, error)
+	getCommitMutex       sync.RWMutex
+	getCommitArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 *github.ListOptions
+	}
+	getCommitReturns struct {
+		result1 *github.RepositoryCommit
+		result2 *github.Response
+		result3 error
+	}
+	getCommitReturnsOnCall map[int]struct {
+		result1 *github.RepositoryCommit
+		result2 *github.Response
+		result3 error
+	}
 	GetContentsStub        func(context.Context, string, string, string, *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error)
 	getContentsMutex       sync.RWMutex
 	getContentsArgsForCall []struct {
@@ -97,6 +116,8 @@ func (fake *FakeRepositories) Get(arg1 context.Context, arg2 string, arg3 string
 func (fake *FakeRepositories) GetCallCount() int {
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
+	fake.getCommitMutex.RLock()
+	defer fake.getCommitMutex.RUnlock()
 	return len(fake.getArgsForCall)
 }
 
@@ -142,6 +163,77 @@ func (fake *FakeRepositories) GetReturnsOnCall(i int, result1 *github.Repository
 	}{result1, result2, result3}
 }
 
+func (fake *FakeRepositories) GetCommit(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 *github.ListOptions) (*github.RepositoryCommit, *github.Response, error) {
+	fake.getCommitMutex.Lock()
+	ret, specificReturn := fake.getCommitReturnsOnCall[len(fake.getCommitArgsForCall)]
+	fake.getCommitArgsForCall = append(fake.getCommitArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 *github.ListOptions
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.GetCommitStub
+	fakeReturns := fake.getCommitReturns
+	fake.recordInvocation("GetCommit", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.getCommitMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeRepositories) GetCommitCallCount() int {
+	fake.getCommitMutex.RLock()
+	defer fake.getCommitMutex.RUnlock()
+	return len(fake.getCommitArgsForCall)
+}
+
+func (fake *FakeRepositories) GetCommitCalls(stub func(context.Context, string, string, string, *github.ListOptions) (*github.RepositoryCommit, *github.Response, error)) {
+	fake.getCommitMutex.Lock()
+	defer fake.getCommitMutex.Unlock()
+	fake.GetCommitStub = stub
+}
+
+func (fake *FakeRepositories) GetCommitArgsForCall(i int) (context.Context, string, string, string, *github.ListOptions) {
+	fake.getCommitMutex.RLock()
+	defer fake.getCommitMutex.RUnlock()
+	argsForCall := fake.getCommitArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeRepositories) GetCommitReturns(result1 *github.RepositoryCommit, result2 *github.Response, result3 error) {
+	fake.getCommitMutex.Lock()
+	defer fake.getCommitMutex.Unlock()
+	fake.GetCommitStub = nil
+	fake.getCommitReturns = struct {
+		result1 *github.RepositoryCommit
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeRepositories) GetCommitReturnsOnCall(i int, result1 *github.RepositoryCommit, result2 *github.Response, result3 error) {
+	fake.getCommitMutex.Lock()
+	defer fake.getCommitMutex.Unlock()
+	fake.GetCommitStub = nil
+	if fake.getCommitReturnsOnCall == nil {
+		fake.getCommitReturnsOnCall = make(map[int]struct {
+			result1 *github.RepositoryCommit
+			result2 *github.Response
+			result3 error
+		})
+	}
+	fake.getCommitReturnsOnCall[i] = struct {
+		result1 *github.RepositoryCommit
+		result2 *github.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeRepositories) GetContents(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 *github.RepositoryContentGetOptions) (*github.RepositoryContent, []*github.RepositoryContent, *github.Response, error) {
 	fake.getContentsMutex.Lock()
 	ret, specificReturn := fake.getContentsReturnsOnCall[len(fake.getContentsArgsForCall)]
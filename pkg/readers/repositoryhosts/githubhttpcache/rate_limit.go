@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package githubhttpcache
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DefaultRateLimitReserve is the number of remaining GitHub API requests at which
+// a RateLimitCoordinator pauses the requests until the rate limit is reset
+const DefaultRateLimitReserve = 10
+
+// RateLimitCoordinator is an http.RoundTripper tracking the rate limit of a GitHub instance for all
+// the workers sharing its client. It reads the X-RateLimit-Remaining and X-RateLimit-Reset headers
+// of the responses and pauses the requests when the remaining requests drop to Reserve until the reset.
+type RateLimitCoordinator struct {
+	// Transport sends the requests, http.DefaultTransport is used if it's nil
+	Transport http.RoundTripper
+	// Reserve is the number of remaining requests kept unused, at least 1 as the GitHub client
+	// fails requests on its own when it has seen the rate limit exhausted
+	Reserve int
+
+	mux       sync.Mutex
+	remaining int
+	reset     time.Time
+	sleep     func(ctx context.Context, d time.Duration) error
+	now       func() time.Time
+}
+
+// NewRateLimitCoordinator creates a RateLimitCoordinator sending the requests with transport
+func NewRateLimitCoordinator(transport http.RoundTripper, reserve int) *RateLimitCoordinator {
+	return &RateLimitCoordinator{Transport: transport, Reserve: reserve}
+}
+
+// RoundTrip implements http.RoundTripper#RoundTrip
+func (c *RateLimitCoordinator) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := c.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err == nil {
+		c.update(resp.Header)
+	}
+	return resp, err
+}
+
+// wait pauses until the rate limit is reset if the remaining requests reached the reserve
+// and otherwise reserves one of them for the request
+func (c *RateLimitCoordinator) wait(ctx context.Context) error {
+	c.mux.Lock()
+	now := c.clock()
+	if c.reset.IsZero() || !now.Before(c.reset) {
+		// the rate limit is unknown or was reset
+		c.reset = time.Time{}
+		c.mux.Unlock()
+		return nil
+	}
+	if c.remaining > max(c.Reserve, 1) {
+		c.remaining--
+		c.mux.Unlock()
+		return nil
+	}
+	d := c.reset.Sub(now)
+	c.mux.Unlock()
+	klog.Warningf("GitHub rate limit nearly exhausted, pausing requests for %s\n", d.Round(time.Second))
+	return c.pause(ctx, d)
+}
+
+// update records the rate limit of a response, it's ignored if the response has no rate limit headers
+func (c *RateLimitCoordinator) update(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	resetTime := time.Unix(reset, 0)
+	// responses of concurrent requests may arrive out of order, keep the lowest remaining count of the latest window
+	if resetTime.Before(c.reset) || resetTime.Equal(c.reset) && remaining >= c.remaining {
+		return
+	}
+	c.remaining, c.reset = remaining, resetTime
+}
+
+func (c *RateLimitCoordinator) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+func (c *RateLimitCoordinator) pause(ctx context.Context, d time.Duration) error {
+	if c.sleep != nil {
+		return c.sleep(ctx, d)
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -7,6 +7,9 @@ package repositoryhosts
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/klog/v2"
@@ -24,6 +27,33 @@ type Registry interface {
 
 type registry struct {
 	repoHosts []RepositoryHost
+	// repository hosts created by registered factories per scheme
+	created map[string]RepositoryHost
+	mux     sync.Mutex
+}
+
+// Factory creates a RepositoryHost for the URIs of a scheme
+type Factory func() RepositoryHost
+
+var (
+	factories   = map[string]Factory{}
+	factoriesMu sync.RWMutex
+)
+
+// RegisterFactory makes a repository host factory available for the URIs of scheme (e.g. s3).
+// Registries use it for URIs that none of their repository hosts accepts, creating the
+// repository host once per registry. It panics if factory is nil or scheme is already registered.
+func RegisterFactory(scheme string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	scheme = strings.ToLower(scheme)
+	if factory == nil {
+		panic("repositoryhosts: RegisterFactory factory is nil")
+	}
+	if _, dup := factories[scheme]; dup {
+		panic("repositoryhosts: RegisterFactory called twice for scheme " + scheme)
+	}
+	factories[scheme] = factory
 }
 
 // NewRegistry creates Registry object, optionally loading it with
@@ -39,9 +69,38 @@ func (r *registry) Get(uri string) (RepositoryHost, error) {
 			return h, nil
 		}
 	}
+	if h := r.fromFactory(uri); h != nil {
+		return h, nil
+	}
 	return nil, fmt.Errorf("no sutiable repository host for %s", uri)
 }
 
+// fromFactory returns the repository host created by the factory registered for the uri scheme
+func (r *registry) fromFactory(uri string) RepositoryHost {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme == "" {
+		return nil
+	}
+	scheme := strings.ToLower(u.Scheme)
+	factoriesMu.RLock()
+	factory, ok := factories[scheme]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.created == nil {
+		r.created = map[string]RepositoryHost{}
+	}
+	h, ok := r.created[scheme]
+	if !ok {
+		h = factory()
+		r.created[scheme] = h
+	}
+	return h
+}
+
 func (r *registry) LogRateLimits(ctx context.Context) {
 	for _, repoHost := range r.repoHosts {
 		l, rr, rt, err := repoHost.GetRateLimit(ctx)
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package repositoryhosts_test
+
+import (
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/repositoryhostsfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var (
+	s3Host    = &repositoryhostsfakes.FakeRepositoryHost{}
+	s3Created = 0
+)
+
+func init() {
+	repositoryhosts.RegisterFactory("S3", func() repositoryhosts.RepositoryHost {
+		s3Created++
+		return s3Host
+	})
+}
+
+var _ = Describe("Registry", func() {
+	var (
+		github   *repositoryhostsfakes.FakeRepositoryHost
+		registry repositoryhosts.Registry
+	)
+	BeforeEach(func() {
+		s3Created = 0
+		github = &repositoryhostsfakes.FakeRepositoryHost{}
+		github.AcceptCalls(func(link string) bool {
+			return link == "https://github.com/gardener/docforge" || link == "s3://claimed/doc.md"
+		})
+		registry = repositoryhosts.NewRegistry(github)
+	})
+	It("returns the repository hosts accepting the URI first", func() {
+		h, err := registry.Get("s3://claimed/doc.md")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(h).To(BeIdenticalTo(github))
+		Expect(s3Created).To(Equal(0))
+	})
+	It("creates the repository host registered for the URI scheme once", func() {
+		for i := 0; i < 2; i++ {
+			h, err := registry.Get("s3://bucket/doc.md")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(h).To(BeIdenticalTo(s3Host))
+		}
+		Expect(s3Created).To(Equal(1))
+	})
+	It("fails for URIs of unregistered schemes", func() {
+		_, err := registry.Get("bitbucket://repo/doc.md")
+		Expect(err).To(MatchError("no sutiable repository host for bitbucket://repo/doc.md"))
+	})
+	It("rejects duplicate registrations", func() {
+		Expect(func() {
+			repositoryhosts.RegisterFactory("s3", func() repositoryhosts.RepositoryHost { return nil })
+		}).To(Panic())
+	})
+})
@@ -8,12 +8,19 @@ package repositoryhosts
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gardener/docforge/pkg/osfakes/httpclient"
 )
 
+// ErrNotFound is matched by errors.Is for the errors of all repository hosts reporting a missing resource
+var ErrNotFound = errors.New("resource not found")
+
 // ErrResourceNotFound indicated that a resource was not found
 type ErrResourceNotFound string
 
@@ -21,12 +28,54 @@ func (e ErrResourceNotFound) Error() string {
 	return fmt.Sprintf("resource %q not found", string(e))
 }
 
+// Is reports ErrResourceNotFound errors as ErrNotFound
+func (e ErrResourceNotFound) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// NotFound returns the ErrResourceNotFound error of resource wrapping the error of the
+// repository host backend (e.g. a GitHub 404 response), if there is one
+func NotFound(resource string, err error) error {
+	if err == nil {
+		return ErrResourceNotFound(resource)
+	}
+	return &notFoundError{resource: ErrResourceNotFound(resource), err: err}
+}
+
+type notFoundError struct {
+	resource ErrResourceNotFound
+	err      error
+}
+
+func (e *notFoundError) Error() string {
+	return fmt.Sprintf("%s: %v", e.resource.Error(), e.err)
+}
+
+func (e *notFoundError) Unwrap() []error {
+	return []error{e.resource, e.err}
+}
+
+// IgnoreFileName is the name of the files listing gitignore-style patterns of the files
+// excluded from a tree, RepositoryHost#Tree lists them along with the content files
+const IgnoreFileName = ".docforgeignore"
+
+// TreeFileURL returns the URL of file, a path listed by RepositoryHost#Tree for treeURL.
+// The URL is not escaped, as are the node sources.
+func TreeFileURL(treeURL string, file string) (string, error) {
+	fileURL, err := url.JoinPath(strings.Replace(treeURL, "/tree/", "/blob/", 1), file)
+	if err != nil {
+		return "", err
+	}
+	// url.JoinPath escapes the path so we revert it
+	return url.PathUnescape(fileURL)
+}
+
 // RepositoryHost does resource specific operations on a type of objects
 // identified by an uri schema that it accepts to handle
 //
 //counterfeiter:generate . RepositoryHost
 type RepositoryHost interface {
-	//Tree Get files that are present in the given url tree
+	//Tree Get files that are present in the given url tree, including the IgnoreFileName files
 	Tree(resourceURL string) ([]string, error)
 	//ToAbsLink Builds the abs link given where it is referenced
 	ToAbsLink(source, link string) (string, error)
@@ -41,19 +90,61 @@ type RepositoryHost interface {
 	GetRawFormatLink(link string) (string, error)
 	// Name of repository host
 	Name() string
-	// GetClient returns an HTTP client for accessing handler's resources
+	// GetClient returns an HTTP client for accessing handler's resources.
+	// Repository hosts without a dedicated client may return nil, the default HTTP client is used then.
 	GetClient() httpclient.Client
 	// GetRateLimit returns rate limit and remaining API calls for the resource handler backend (e.g. GitHub RateLimit)
 	// returns negative values if RateLimit is not applicable
 	GetRateLimit(ctx context.Context) (int, int, time.Time, error)
 }
 
+// StreamReader is implemented by repository hosts that can read a resource without buffering its whole content
+type StreamReader interface {
+	// ReadStream opens a resource content at uri for reading, the caller must close it
+	ReadStream(ctx context.Context, resourceURL string) (io.ReadCloser, error)
+}
+
+// BlobHasher is implemented by repository hosts that know the git blob SHA of a resource without reading it
+type BlobHasher interface {
+	// BlobSHA returns the git blob SHA of the resource at resourceURL, false if it's unknown
+	BlobSHA(resourceURL string) (string, bool)
+}
+
+// ResourceEntry is an entry of a directory listing
+type ResourceEntry struct {
+	// Name of the file or directory
+	Name string
+	// IsDir is true for directories
+	IsDir bool
+	// Size of the file in bytes
+	Size int64
+}
+
+// Lister is implemented by repository hosts that can list a directory without resolving its files tree
+type Lister interface {
+	// List returns the entries of a single level of the tree resource at resourceURL
+	List(ctx context.Context, resourceURL string) ([]ResourceEntry, error)
+}
+
+// MoveTracker is implemented by repository hosts that can tell where a resource was moved or renamed
+type MoveTracker interface {
+	// MovedTo returns the URL of the resource at resourceURL after its last move or rename,
+	// or an empty string if the move is unknown
+	MovedTo(ctx context.Context, resourceURL string) (string, error)
+}
+
 // RepositoryHostOptions options for the resource handler
 type RepositoryHostOptions struct {
-	CacheHomeDir     string            `mapstructure:"cache-dir"`
-	Credentials      map[string]string `mapstructure:"github-oauth-token-map"`
-	ResourceMappings map[string]string `mapstructure:"resourceMappings"`
-	Hugo             bool              `mapstructure:"hugo"`
+	CacheHomeDir      string            `mapstructure:"cache-dir"`
+	Credentials       map[string]string `mapstructure:"github-oauth-token-map"`
+	ResourceMappings  map[string]string `mapstructure:"resourceMappings"`
+	Hugo              bool              `mapstructure:"hugo"`
+	APIBaseURLs       map[string]string `mapstructure:"github-api-base-url-map"`
+	UploadURLs        map[string]string `mapstructure:"github-upload-url-map"`
+	UserAgent         string            `mapstructure:"github-user-agent"`
+	Headers           map[string]string `mapstructure:"github-headers"`
+	GitInfoDateFormat string            `mapstructure:"github-info-date-format"`
+	RateLimitReserve  int               `mapstructure:"github-rate-limit-reserve"`
 }
 
 // Credential holds repository credential data
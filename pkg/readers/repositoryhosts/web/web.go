@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package web
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gardener/docforge/pkg/osfakes/httpclient"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+)
+
+// Web is a repositoryhosts.RepositoryHost for resources at plain http(s) URLs. Register it after
+// the other repository hosts as it accepts any http(s) URL, e.g. the URLs of GitHub repositories.
+type Web struct {
+	client httpclient.Client
+}
+
+// New creates a repository host reading the resources with client
+func New(client httpclient.Client) repositoryhosts.RepositoryHost {
+	return &Web{client: client}
+}
+
+// Name returns the repository host name
+func (w *Web) Name() string {
+	return "web"
+}
+
+// Accept implements the repositoryhosts.RepositoryHost#Accept
+func (w *Web) Accept(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+// Read implements the repositoryhosts.RepositoryHost#Read
+func (w *Web) Read(ctx context.Context, resourceURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resourceURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s fails: %w", resourceURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, repositoryhosts.NotFound(resourceURL, fmt.Errorf("HTTP Status %s", resp.Status))
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("reading %s fails with HTTP status: %d", resourceURL, resp.StatusCode)
+	}
+	cnt, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s fails: %w", resourceURL, err)
+	}
+	return cnt, nil
+}
+
+// Tree implements the repositoryhosts.RepositoryHost#Tree, web resources can't be listed
+func (w *Web) Tree(resourceURL string) ([]string, error) {
+	return nil, fmt.Errorf("listing the files of %s is not supported for web resources", resourceURL)
+}
+
+// ToAbsLink implements the repositoryhosts.RepositoryHost#ToAbsLink by resolving link against source
+func (w *Web) ToAbsLink(source, link string) (string, error) {
+	base, err := url.Parse(source)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// ReadGitInfo implements the repositoryhosts.RepositoryHost#ReadGitInfo, web resources have no git info
+func (w *Web) ReadGitInfo(_ context.Context, _ string) ([]byte, error) {
+	return nil, nil
+}
+
+// GetRawFormatLink implements the repositoryhosts.RepositoryHost#GetRawFormatLink, web resources are served as they are
+func (w *Web) GetRawFormatLink(link string) (string, error) {
+	return link, nil
+}
+
+// GetClient implements the repositoryhosts.RepositoryHost#GetClient
+func (w *Web) GetClient() httpclient.Client {
+	return w.client
+}
+
+// GetRateLimit implements the repositoryhosts.RepositoryHost#GetRateLimit, web hosts have no known rate limit
+func (w *Web) GetRateLimit(_ context.Context) (int, int, time.Time, error) {
+	return -1, -1, time.Now(), nil
+}
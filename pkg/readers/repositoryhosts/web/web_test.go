@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package web_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/web"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func TestWeb(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Web Suite")
+}
+
+var _ = Describe("Web", func() {
+	var (
+		server *httptest.Server
+		host   repositoryhosts.RepositoryHost
+	)
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/docs/guide.md":
+				_, _ = w.Write([]byte("# Guide\n"))
+			case "/docs/broken.md":
+				w.WriteHeader(http.StatusInternalServerError)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		host = web.New(server.Client())
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	DescribeTable("#Accept",
+		func(link string, expected bool) {
+			Expect(host.Accept(link)).To(Equal(expected))
+		},
+		Entry("https URL", "https://example.com/docs/guide.md", true),
+		Entry("http URL", "http://example.com/docs/guide.md", true),
+		Entry("relative link", "docs/guide.md", false),
+		Entry("file URL", "file:///docs/guide.md", false),
+		Entry("mailto link", "mailto:a@b.c", false),
+	)
+
+	Describe("#Read", func() {
+		It("reads the content with the client", func() {
+			content, err := host.Read(context.TODO(), server.URL+"/docs/guide.md")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("# Guide\n"))
+		})
+		It("reports missing resources as not found", func() {
+			_, err := host.Read(context.TODO(), server.URL+"/docs/missing.md")
+			Expect(errors.Is(err, repositoryhosts.ErrNotFound)).To(BeTrue())
+		})
+		It("fails for error responses", func() {
+			_, err := host.Read(context.TODO(), server.URL+"/docs/broken.md")
+			Expect(err).To(MatchError(ContainSubstring("HTTP status: 500")))
+			Expect(errors.Is(err, repositoryhosts.ErrNotFound)).To(BeFalse())
+		})
+	})
+
+	DescribeTable("#ToAbsLink",
+		func(source, link, expected string) {
+			abs, err := host.ToAbsLink(source, link)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(abs).To(Equal(expected))
+		},
+		Entry("relative link", "https://example.com/docs/guide.md", "setup.md", "https://example.com/docs/setup.md"),
+		Entry("parent link", "https://example.com/docs/guide.md", "../images/logo.png", "https://example.com/images/logo.png"),
+		Entry("root relative link", "https://example.com/docs/guide.md", "/blog/post.md#intro", "https://example.com/blog/post.md#intro"),
+		Entry("absolute URL", "https://example.com/docs/guide.md", "https://other.com/a.md", "https://other.com/a.md"),
+	)
+
+	It("reads the resources of relative links", func() {
+		abs, err := host.ToAbsLink(server.URL+"/docs/setup/intro.md", "../guide.md")
+		Expect(err).NotTo(HaveOccurred())
+		content, err := host.Read(context.TODO(), abs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("# Guide\n"))
+	})
+})
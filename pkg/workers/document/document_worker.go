@@ -16,11 +16,13 @@ import (
 	"github.com/gardener/docforge/cmd/hugo"
 	"github.com/gardener/docforge/pkg/manifest"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/githubhttpcache"
 	"github.com/gardener/docforge/pkg/workers/document/frontmatter"
 	"github.com/gardener/docforge/pkg/workers/document/markdown"
 	"github.com/gardener/docforge/pkg/workers/downloader"
 	"github.com/gardener/docforge/pkg/workers/linkresolver"
 	"github.com/gardener/docforge/pkg/workers/linkvalidator"
+	"github.com/gardener/docforge/pkg/workers/taskqueue"
 	"github.com/gardener/docforge/pkg/writers"
 	"github.com/yuin/goldmark/ast"
 	"k8s.io/klog/v2"
@@ -37,7 +39,9 @@ type Worker struct {
 	resourcesRoot string
 
 	Repositoryhosts repositoryhosts.Registry
-	Hugo            hugo.Hugo
+	// Sources reads the document sources, it may handle URLs that links to are kept external, e.g. plain web URLs
+	Sources repositoryhosts.Registry
+	Hugo    hugo.Hugo
 }
 
 // docContent defines a document content
@@ -47,8 +51,9 @@ type docContent struct {
 	docURI string
 }
 
-// NewDocumentWorker creates Worker objects
-func NewDocumentWorker(resourcesRoot string, downloader downloader.Interface, validator linkvalidator.Interface, linkResolver linkresolver.Interface, rh repositoryhosts.Registry, hugo hugo.Hugo, writer writers.Writer) *Worker {
+// NewDocumentWorker creates Worker objects. The document sources are read with sources,
+// rh is used for the links in the documents.
+func NewDocumentWorker(resourcesRoot string, downloader downloader.Interface, validator linkvalidator.Interface, linkResolver linkresolver.Interface, rh repositoryhosts.Registry, sources repositoryhosts.Registry, hugo hugo.Hugo, writer writers.Writer) *Worker {
 	return &Worker{
 		linkResolver,
 		downloader,
@@ -56,6 +61,7 @@ func NewDocumentWorker(resourcesRoot string, downloader downloader.Interface, va
 		writer,
 		resourcesRoot,
 		rh,
+		sources,
 		hugo,
 	}
 }
@@ -69,8 +75,13 @@ var (
 	}
 )
 
-// ProcessNode processes a node and writes its content
+// ProcessNode processes a node and writes its content. The returned errors are taskqueue.WorkerError
+// classifying GitHub API errors as transient or permanent.
 func (d *Worker) ProcessNode(ctx context.Context, node *manifest.Node) error {
+	return taskqueue.NewWorkerError(d.processNode(ctx, node), githubhttpcache.IsTransient)
+}
+
+func (d *Worker) processNode(ctx context.Context, node *manifest.Node) error {
 	var cnt []byte
 	if node.HasContent() {
 		// Process the node
@@ -86,7 +97,8 @@ func (d *Worker) ProcessNode(ctx context.Context, node *manifest.Node) error {
 		}
 		cnt = bytesBuff.Bytes()
 	}
-	if err := d.writer.Write(node.Name(), node.Path, cnt, node); err != nil {
+	// the node properties may override the extension of the written file
+	if err := d.writer.Write(path.Base(node.OutputPath("")), node.Path, cnt, node); err != nil {
 		return err
 	}
 	return nil
@@ -125,6 +137,7 @@ func (d *Worker) process(ctx context.Context, b *bytes.Buffer, n *manifest.Node)
 		}
 		frontmatter.MoveMultiSourceFrontmatterToTopDocument(docs)
 		frontmatter.MergeDocumentAndNodeFrontmatter(firstDoc, n)
+		frontmatter.ComputeNodeDisplayTitle(firstDoc, markdown.FirstHeading(firstDoc, fullContent[0].docCnt), n)
 		frontmatter.ComputeNodeTitle(firstDoc, n, d.Hugo.IndexFileNames, d.Hugo.Enabled)
 	}
 	// 2. - write node content
@@ -144,7 +157,10 @@ func (d *Worker) process(ctx context.Context, b *bytes.Buffer, n *manifest.Node)
 
 func (d *Worker) processSource(ctx context.Context, sourceType string, source string, nodePath string) (*docContent, error) {
 	var dc *docContent
-	repoHost, err := d.Repositoryhosts.Get(source)
+	// a source fragment selects the lines of a line range, e.g. L10-L20,
+	// or the section under the heading with that link fragment
+	source, section, _ := strings.Cut(source, "#")
+	repoHost, err := d.Sources.Get(source)
 	if err != nil {
 		return nil, err
 	}
@@ -152,6 +168,15 @@ func (d *Worker) processSource(ctx context.Context, sourceType string, source st
 	if err != nil {
 		return nil, fmt.Errorf("reading %s %s from node %s failed: %w", sourceType, source, nodePath, err)
 	}
+	if first, last, ok := markdown.ParseLineRange(section); ok {
+		if content, err = markdown.ExtractLines(content, first, last); err != nil {
+			return nil, fmt.Errorf("selecting %s %s#%s from node %s failed: %w", sourceType, source, section, nodePath, err)
+		}
+	} else if section != "" {
+		if content, err = markdown.ExtractSection(content, section); err != nil {
+			return nil, fmt.Errorf("selecting %s %s#%s from node %s failed: %w", sourceType, source, section, nodePath, err)
+		}
+	}
 	dc = &docContent{docCnt: content, docURI: source}
 	dc.docAst, err = markdown.Parse(content)
 	if err != nil {
@@ -179,7 +204,11 @@ func (d *linkResolverTask) resolveLink(dest string, isEmbeddable bool) (string,
 		return dest, err
 	}
 	if shouldValidate && !downloadEmbeddable(url) {
-		d.validator.ValidateLink(dest, d.Source)
+		if isEmbeddable {
+			d.validator.ValidateImage(dest, d.Source)
+		} else {
+			d.validator.ValidateLink(dest, d.Source)
+		}
 	}
 	if !isEmbeddable {
 		return newLink, nil
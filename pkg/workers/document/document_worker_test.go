@@ -7,8 +7,12 @@ package document_test
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -22,8 +26,12 @@ import (
 	"github.com/gardener/docforge/pkg/workers/downloader/downloaderfakes"
 	"github.com/gardener/docforge/pkg/workers/linkresolver/linkresolverfakes"
 	"github.com/gardener/docforge/pkg/workers/linkvalidator/linkvalidatorfakes"
+	"github.com/gardener/docforge/pkg/workers/taskqueue"
+	"github.com/gardener/docforge/pkg/writers"
 	"github.com/gardener/docforge/pkg/writers/writersfakes"
+	"github.com/google/go-github/v43/github"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -39,7 +47,8 @@ var _ = Describe("Document resolving", func() {
 	var (
 		dw *document.Worker
 
-		w *writersfakes.FakeWriter
+		w         *writersfakes.FakeWriter
+		newWorker func(writer writers.Writer) *document.Worker
 	)
 	BeforeEach(func() {
 		localHost := repositoryhostsfakes.FakeRepositoryHost{}
@@ -53,6 +62,11 @@ var _ = Describe("Document resolving", func() {
 			return u.ResolveReference(ulink).String(), nil
 		})
 		localHost.ReadCalls(func(ctx context.Context, s string) ([]byte, error) {
+			// sources below status/ fail with a GitHub API error of that status code, e.g. status/502.md
+			var code int
+			if _, err := fmt.Sscanf(s, "https://github.com/fake_owner/fake_repo/blob/master/status/%d.md", &code); err == nil {
+				return nil, &github.ErrorResponse{Response: &http.Response{StatusCode: code, Request: &http.Request{Method: http.MethodGet, URL: &url.URL{}}}}
+			}
 			if strings.HasPrefix(s, "https://github.com/fake_owner/fake_repo/blob/master/") {
 				return manifests.ReadFile("tests/" + strings.TrimPrefix(s, "https://github.com/fake_owner/fake_repo/blob/master/"))
 			}
@@ -77,11 +91,35 @@ var _ = Describe("Document resolving", func() {
 		lrf.ResolveLinkCalls(func(s1 string, n *manifest.Node, s2 string) (string, bool, error) {
 			return s1, true, nil
 		})
+		newWorker = func(writer writers.Writer) *document.Worker {
+			return document.NewDocumentWorker("__resources", df, vf, lrf, registry, registry, hugo, writer)
+		}
 		w = &writersfakes.FakeWriter{}
-		dw = document.NewDocumentWorker("__resources", df, vf, lrf, registry, hugo, w)
+		dw = newWorker(w)
 	})
 
 	Context("#ProcessNode", func() {
+		DescribeTable("classifies GitHub API errors",
+			func(code int, transient bool) {
+				node := &manifest.Node{
+					FileType: manifest.FileType{
+						File:   "node",
+						Source: fmt.Sprintf("https://github.com/fake_owner/fake_repo/blob/master/status/%d.md", code),
+					},
+					Type: "file",
+					Path: "one",
+				}
+				err := dw.ProcessNode(context.TODO(), node)
+				var wErr *taskqueue.WorkerError
+				Expect(errors.As(err, &wErr)).To(BeTrue())
+				Expect(wErr.Transient).To(Equal(transient))
+				Expect(taskqueue.IsTransient(err)).To(Equal(transient))
+				Expect(w.WriteCallCount()).To(Equal(0))
+			},
+			Entry("bad gateway is transient", http.StatusBadGateway, true),
+			Entry("not found is permanent", http.StatusNotFound, false),
+		)
+
 		It("returns correct multisource content", func() {
 			node := &manifest.Node{
 				FileType: manifest.FileType{
@@ -126,5 +164,81 @@ var _ = Describe("Document resolving", func() {
 			Expect(node).To(Equal(nodegot))
 		})
 
+		It("returns the section selected by the source fragment", func() {
+			node := &manifest.Node{
+				FileType: manifest.FileType{
+					File:   "node",
+					Source: "https://github.com/fake_owner/fake_repo/blob/master/sections.md#install",
+				},
+				Type: "file",
+				Path: "one",
+			}
+			err := dw.ProcessNode(context.TODO(), node)
+			Expect(err).ToNot(HaveOccurred())
+			_, _, cnt, _ := w.WriteArgsForCall(0)
+			Expect(string(cnt)).To(Equal("---\ntitle: Node\n---\n\n## Install\n\nRun it.\n"))
+		})
+
+		It("returns the lines selected by the source fragment", func() {
+			node := &manifest.Node{
+				FileType: manifest.FileType{
+					File:   "node",
+					Source: "https://github.com/fake_owner/fake_repo/blob/master/sections.md#L5-L7",
+				},
+				Type: "file",
+				Path: "one",
+			}
+			err := dw.ProcessNode(context.TODO(), node)
+			Expect(err).ToNot(HaveOccurred())
+			_, _, cnt, _ := w.WriteArgsForCall(0)
+			Expect(string(cnt)).To(Equal("---\ntitle: Node\n---\n\n## Install\n\nRun it.\n"))
+		})
+
+		It("clamps the selected lines to the source", func() {
+			node := &manifest.Node{
+				FileType: manifest.FileType{
+					File:   "node",
+					Source: "https://github.com/fake_owner/fake_repo/blob/master/sections.md#L9-L100",
+				},
+				Type: "file",
+				Path: "one",
+			}
+			err := dw.ProcessNode(context.TODO(), node)
+			Expect(err).ToNot(HaveOccurred())
+			_, _, cnt, _ := w.WriteArgsForCall(0)
+			Expect(string(cnt)).To(Equal("---\ntitle: Node\n---\n\n## Usage\n\nCall it.\n"))
+		})
+
+		It("fails for inverted line ranges", func() {
+			node := &manifest.Node{
+				FileType: manifest.FileType{
+					File:   "node",
+					Source: "https://github.com/fake_owner/fake_repo/blob/master/sections.md#L7-L5",
+				},
+				Type: "file",
+				Path: "one",
+			}
+			err := dw.ProcessNode(context.TODO(), node)
+			Expect(err).To(MatchError(ContainSubstring("inverted line range L7-L5")))
+		})
+
+		It("writes the file with the extension of the node properties", func() {
+			dir, err := os.MkdirTemp("", "document")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+			node := &manifest.Node{
+				FileType: manifest.FileType{
+					File:   "node.md",
+					Source: "https://github.com/fake_owner/fake_repo/blob/master/target.md",
+				},
+				Type:       "file",
+				Path:       "one",
+				Properties: map[string]interface{}{manifest.OutputExtensionProperty: "html"},
+			}
+			Expect(newWorker(&writers.FSWriter{Root: dir}).ProcessNode(context.TODO(), node)).To(Succeed())
+			Expect(filepath.Join(dir, "one", "node.html")).To(BeARegularFile())
+			Expect(filepath.Join(dir, "one", "node.md")).NotTo(BeAnExistingFile())
+		})
+
 	})
 })
@@ -88,6 +88,28 @@ func ComputeNodeTitle(nodeAst NodeMeta, node *manifest.Node, IndexFileNames []st
 	nodeAst.SetMeta(docFrontmatter)
 }
 
+// ComputeNodeDisplayTitle stores the node display title in its Properties["title"]
+// unless the manifest sets it. The title is taken from the document frontmatter `title`,
+// falling back to the document first level one heading and then to the node name.
+func ComputeNodeDisplayTitle(nodeAst NodeMeta, heading string, node *manifest.Node) {
+	if node == nil {
+		return
+	}
+	if _, ok := node.Properties["title"]; ok {
+		return
+	}
+	title := node.Name()
+	if heading != "" {
+		title = heading
+	}
+	if nodeAst != nil {
+		if t, ok := nodeAst.Meta()["title"].(string); ok && t != "" {
+			title = t
+		}
+	}
+	node.SetProperty("title", title)
+}
+
 // Compares a node name to the configured list of index file
 // and a default name '_index.md' to determine if this node
 // is an index document node.
@@ -15,8 +15,11 @@ import (
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts/repositoryhostsfakes"
 	"github.com/gardener/docforge/pkg/workers/document/frontmatter"
 	"github.com/gardener/docforge/pkg/workers/document/frontmatter/frontmatterfakes"
+	"github.com/gardener/docforge/pkg/workers/document/markdown"
+	"github.com/yuin/goldmark/ast"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -205,5 +208,42 @@ var _ = Describe("Document frontmatter", func() {
 
 		})
 	})
+	Context("#ComputeNodeDisplayTitle", func() {
+		var (
+			nodes []*manifest.Node
+			node  *manifest.Node
+			err   error
+		)
+		BeforeEach(func() {
+			nodes, err = manifest.ResolveManifest("tests/titles.yaml", repositoryhostsfakes.FilesystemRegistry(manifests))
+			Expect(err).NotTo(HaveOccurred())
+			node = nodes[1]
+		})
+		DescribeTable("title sources",
+			func(fixture string, expected string) {
+				content, err := manifests.ReadFile(fixture)
+				Expect(err).NotTo(HaveOccurred())
+				doc, err := markdown.Parse(content)
+				Expect(err).NotTo(HaveOccurred())
+				frontmatter.ComputeNodeDisplayTitle(doc.(*ast.Document), markdown.FirstHeading(doc, content), node)
+				Expect(node.Properties).To(HaveKeyWithValue("title", expected))
+			},
+			Entry("uses frontmatter title", "tests/frontmatter_title.md", "Frontmatter Title"),
+			Entry("falls back to first level one heading", "tests/heading_title.md", "Heading Title"),
+			Entry("falls back to node name", "tests/plain.md", "file_node-1.md"),
+		)
+		It("keeps the title set in the manifest", func() {
+			node.SetProperty("title", "Manifest Title")
+			nodeAst := &frontmatterfakes.FakeNodeMeta{}
+			nodeAst.MetaReturns(map[string]interface{}{"title": "Frontmatter Title"})
+			frontmatter.ComputeNodeDisplayTitle(nodeAst, "Heading", node)
+			Expect(node.Properties).To(HaveKeyWithValue("title", "Manifest Title"))
+		})
+		It("doesn't change anything if node is nil", func() {
+			nodeAst := &frontmatterfakes.FakeNodeMeta{}
+			frontmatter.ComputeNodeDisplayTitle(nodeAst, "Heading", nil)
+			Expect(nodeAst.MetaCallCount()).To(Equal(0))
+		})
+	})
 
 })
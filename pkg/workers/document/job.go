@@ -31,10 +31,11 @@ type Processor interface {
 	ProcessNode(node *manifest.Node) bool
 }
 
-// New creates a new Worker
-func New(workerCount int, failFast bool, wg *sync.WaitGroup, structure []*manifest.Node, resourcesRoot string, downloadJob downloader.Interface, validator linkvalidator.Interface, rhs repositoryhosts.Registry, hugo hugo.Hugo, writer writers.Writer) (Processor, taskqueue.QueueController, error) {
+// New creates a new Worker. The document sources are read with sources, rhs is used for the links in the documents.
+func New(workerCount int, failFast bool, wg *sync.WaitGroup, structure []*manifest.Node, resourcesRoot string, downloadJob downloader.Interface, validator linkvalidator.Interface, rhs repositoryhosts.Registry, sources repositoryhosts.Registry, hugo hugo.Hugo, writer writers.Writer) (Processor, taskqueue.QueueController, error) {
 	lr := &linkresolver.LinkResolver{
 		Repositoryhosts: rhs,
+		Sources:         sources,
 		Hugo:            hugo,
 		SourceToNode:    make(map[string][]*manifest.Node),
 	}
@@ -47,7 +48,7 @@ func New(workerCount int, failFast bool, wg *sync.WaitGroup, structure []*manife
 			}
 		}
 	}
-	worker := NewDocumentWorker(resourcesRoot, downloadJob, validator, lr, rhs, hugo, writer)
+	worker := NewDocumentWorker(resourcesRoot, downloadJob, validator, lr, rhs, sources, hugo, writer)
 	queue, err := taskqueue.New("Document", workerCount, worker.execute, failFast, wg)
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var lineRange = regexp.MustCompile(`^L(\d+)(?:-L(\d+))?$`)
+
+// ParseLineRange parses a source link fragment selecting the lines first to last, e.g. `L10-L20`,
+// or the single line first, e.g. `L10`. It returns false if fragment is not a line range.
+func ParseLineRange(fragment string) (int, int, bool) {
+	m := lineRange.FindStringSubmatch(fragment)
+	if m == nil {
+		return 0, 0, false
+	}
+	first, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	last := first
+	if m[2] != "" {
+		if last, err = strconv.Atoi(m[2]); err != nil {
+			return 0, 0, false
+		}
+	}
+	return first, last, true
+}
+
+// ExtractLines returns the lines first to last of source, counting from 1. The range is
+// clamped to the lines of source and an inverted range, where last is before first, fails.
+func ExtractLines(source []byte, first int, last int) ([]byte, error) {
+	if last < first {
+		return nil, fmt.Errorf("inverted line range L%d-L%d", first, last)
+	}
+	if first < 1 {
+		first = 1
+	}
+	start, line := 0, 1
+	for ; line < first && start < len(source); line++ {
+		start += lineLength(source[start:])
+	}
+	end := start
+	for ; line <= last && end < len(source); line++ {
+		end += lineLength(source[end:])
+	}
+	return source[start:end], nil
+}
+
+// lineLength returns the length of the first line of source including its line break
+func lineLength(source []byte) int {
+	if i := bytes.IndexByte(source, '\n'); i >= 0 {
+		return i + 1
+	}
+	return len(source)
+}
@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package markdown_test
+
+import (
+	"github.com/gardener/docforge/pkg/workers/document/markdown"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lines", func() {
+	DescribeTable("parsing line ranges",
+		func(fragment string, first int, last int, ok bool) {
+			f, l, isRange := markdown.ParseLineRange(fragment)
+			Expect(isRange).To(Equal(ok))
+			Expect(f).To(Equal(first))
+			Expect(l).To(Equal(last))
+		},
+		Entry("range", "L10-L20", 10, 20, true),
+		Entry("single line", "L7", 7, 7, true),
+		Entry("heading link fragment", "install", 0, 0, false),
+		Entry("incomplete range", "L10-", 0, 0, false),
+		Entry("lowercase", "l10-l20", 0, 0, false),
+	)
+	md := "one\ntwo\nthree\nfour"
+	DescribeTable("extracting",
+		func(first int, last int, expected string) {
+			got, err := markdown.ExtractLines([]byte(md), first, last)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(got)).To(Equal(expected))
+		},
+		Entry("middle lines", 2, 3, "two\nthree\n"),
+		Entry("single line", 1, 1, "one\n"),
+		Entry("last line without line break", 4, 4, "four"),
+		Entry("range clamped to the end", 3, 100, "three\nfour"),
+		Entry("range clamped to the start", 0, 1, "one\n"),
+		Entry("range after the end", 10, 20, ""),
+	)
+	It("fails for inverted ranges", func() {
+		_, err := markdown.ExtractLines([]byte(md), 3, 2)
+		Expect(err).To(MatchError("inverted line range L3-L2"))
+	})
+})
@@ -772,7 +772,7 @@ func (r *Renderer) modifyHTMLTags(source []byte, target io.Writer) (bool, error)
 		t := z.Token()
 		if "a" == t.Data {
 			for i, a := range t.Attr {
-				if a.Key == "href" {
+				if a.Key == "href" && !isDataURI(a.Val) {
 					dest, err := r.linkResolver(a.Val, false)
 					if err != nil {
 						return modified, err
@@ -786,7 +786,7 @@ func (r *Renderer) modifyHTMLTags(source []byte, target io.Writer) (bool, error)
 			}
 		} else if "img" == t.Data {
 			for i, a := range t.Attr {
-				if a.Key == "src" {
+				if a.Key == "src" && !isDataURI(a.Val) {
 					dest, err := r.linkResolver(a.Val, true)
 					if err != nil {
 						return modified, err
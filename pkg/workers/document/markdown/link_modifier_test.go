@@ -210,6 +210,16 @@ var _ = Describe("Links modifier", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(buf.Bytes()).To(Equal([]byte(exp)))
 		})
+		Context("data URI images", func() {
+			BeforeEach(func() {
+				md = "foo <img src=\"data:image/png;base64,AAAA\" alt=\"baz\"/>\n"
+				exp = md
+			})
+			It("does not modify the images", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buf.Bytes()).To(Equal([]byte(exp)))
+			})
+		})
 		Context("images in comments", func() {
 			BeforeEach(func() {
 				md = "block:\n<!-- <p>\n<img src=\"/foo\" alt=\"bar\" title=\"baz\"/>\n</p> -->\n\nrow:\nfoo <!-- <img src=\"/bar\" alt=\"baz\"/> -->\n"
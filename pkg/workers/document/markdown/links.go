@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package markdown
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"golang.org/x/net/html"
+)
+
+// LinkKind is the markdown syntax a link is written with
+type LinkKind string
+
+const (
+	// KindInline is an inline link `[text](destination)`
+	KindInline LinkKind = "inline"
+	// KindReference is a reference link `[text][label]` resolved with a link reference definition
+	KindReference LinkKind = "reference"
+	// KindImage is an image `![alt](source)` or `![alt][label]`
+	KindImage LinkKind = "image"
+	// KindAutolink is an autolink `<https://...>` or a bare URL
+	KindAutolink LinkKind = "autolink"
+	// KindHTML is a `href` of an HTML anchor or a `src` of an HTML image
+	KindHTML LinkKind = "html"
+)
+
+// Link is a link found in markdown content
+type Link struct {
+	// Destination of the link or source of the image
+	Destination string
+	// Kind of the link
+	Kind LinkKind
+	// Offset is the byte offset of the link in the markdown content, -1 if it can't be determined
+	Offset int
+	// Line is the 1-based line of the link in the markdown content, 0 if it can't be determined
+	Line int
+}
+
+// ExtractLinks returns the links, images, autolinks and embedded HTML links in markdown content in document order
+func ExtractLinks(markdown []byte) []Link {
+	doc := gmParser.Parser().Parse(text.NewReader(markdown))
+	var links []Link
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch v := n.(type) {
+		case *ast.Link:
+			kind := KindInline
+			if isReference(n, markdown) {
+				kind = KindReference
+			}
+			links = append(links, newLink(markdown, string(v.Destination), kind, linkOffset(n, markdown)))
+		case *ast.Image:
+			links = append(links, newLink(markdown, string(v.Destination), KindImage, linkOffset(n, markdown)))
+		case *ast.AutoLink:
+			offset := -1
+			if label := v.Label(markdown); len(label) > 0 {
+				// label is a subslice of markdown
+				offset = cap(markdown) - cap(label)
+				if offset > 0 && markdown[offset-1] == '<' {
+					offset--
+				}
+			}
+			links = append(links, newLink(markdown, string(v.URL(markdown)), KindAutolink, offset))
+		case *ast.HTMLBlock:
+			// HTMLBlockType 6 & 7 may contain links and images
+			if v.HTMLBlockType >= ast.HTMLBlockType6 && v.Lines().Len() > 0 {
+				links = append(links, extractHTMLLinks(markdown, v.Lines().At(0).Start, v.Lines().At(v.Lines().Len()-1).Stop)...)
+			}
+		case *ast.RawHTML:
+			if v.Segments.Len() > 0 {
+				links = append(links, extractHTMLLinks(markdown, v.Segments.At(0).Start, v.Segments.At(v.Segments.Len()-1).Stop)...)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return links
+}
+
+// ExtractImages returns the images and the embedded HTML images in markdown content in document order
+func ExtractImages(markdown []byte) []Link {
+	var images []Link
+	for _, link := range ExtractLinks(markdown) {
+		if link.Kind == KindImage || link.Kind == KindHTML && isImageTag(markdown, link.Offset) {
+			images = append(images, link)
+		}
+	}
+	return images
+}
+
+// isImageTag checks if the HTML tag at offset is an `img` tag
+func isImageTag(source []byte, offset int) bool {
+	if offset < 0 || offset+5 > len(source) {
+		return false
+	}
+	tag := source[offset : offset+5]
+	return bytes.EqualFold(tag[:4], []byte("<img")) && strings.ContainsRune(" \t\r\n/>", rune(tag[4]))
+}
+
+// ExtractHTMLLinks returns the `href` attributes of anchors and the `src` attributes of images in HTML content.
+// `data:` URIs are skipped.
+func ExtractHTMLLinks(content []byte) []Link {
+	return extractHTMLLinks(content, 0, len(content))
+}
+
+func extractHTMLLinks(source []byte, start int, stop int) []Link {
+	var links []Link
+	offset := start
+	z := html.NewTokenizer(bytes.NewReader(source[start:stop]))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return links
+		}
+		raw := len(z.Raw())
+		if tt == html.StartTagToken || tt == html.SelfClosingTagToken {
+			t := z.Token()
+			attr := ""
+			switch t.Data {
+			case "a":
+				attr = "href"
+			case "img":
+				attr = "src"
+			}
+			for _, a := range t.Attr {
+				if a.Key == attr && !isDataURI(a.Val) {
+					links = append(links, newLink(source, a.Val, KindHTML, offset))
+					break
+				}
+			}
+		}
+		offset += raw
+	}
+}
+
+// isDataURI checks if a link embeds its content as a `data:` URI
+func isDataURI(link string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(link)), "data:")
+}
+
+func newLink(markdown []byte, destination string, kind LinkKind, offset int) Link {
+	l := Link{Destination: destination, Kind: kind, Offset: offset}
+	if offset >= 0 {
+		l.Line = bytes.Count(markdown[:offset], []byte("\n")) + 1
+	}
+	return l
+}
+
+// linkOffset finds the opening bracket of a link or image by walking back from its first text segment
+func linkOffset(n ast.Node, source []byte) int {
+	first, depth := textSegment(n, true)
+	if first == nil {
+		return -1
+	}
+	for i := first.Start - 1; i >= 0; i-- {
+		if source[i] != '[' {
+			continue
+		}
+		if depth--; depth == 0 {
+			if n.Kind() == ast.KindImage && i > 0 && source[i-1] == '!' {
+				return i - 1
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// isReference checks if the closing bracket of a link is followed by an inline destination
+func isReference(n ast.Node, source []byte) bool {
+	last, depth := textSegment(n, false)
+	if last == nil {
+		return false
+	}
+	for i := last.Stop; i < len(source); i++ {
+		if source[i] != ']' {
+			continue
+		}
+		if depth--; depth == 0 {
+			return i+1 >= len(source) || source[i+1] != '('
+		}
+	}
+	return false
+}
+
+// textSegment returns the first or last text segment in a link or image together with
+// the number of links and images it is nested in
+func textSegment(n ast.Node, first bool) (*text.Segment, int) {
+	depth := 0
+	for n != nil {
+		if n.Kind() == ast.KindLink || n.Kind() == ast.KindImage {
+			depth++
+		}
+		if t, ok := n.(*ast.Text); ok {
+			return &t.Segment, depth
+		}
+		if first {
+			n = n.FirstChild()
+		} else {
+			n = n.LastChild()
+		}
+	}
+	return nil, depth
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package markdown_test
+
+import (
+	"github.com/gardener/docforge/pkg/workers/document/markdown"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Links", func() {
+	DescribeTable("ExtractLinks",
+		func(md string, expected []markdown.Link) {
+			Expect(markdown.ExtractLinks([]byte(md))).To(Equal(expected))
+		},
+		Entry("no links", "# Title\n\nplain text\n", nil),
+		Entry("inline link", "see [docs](./docs.md \"title\")", []markdown.Link{
+			{Destination: "./docs.md", Kind: markdown.KindInline, Offset: 4, Line: 1},
+		}),
+		Entry("inline link with emphasis", "x\n[*docs*](/docs)", []markdown.Link{
+			{Destination: "/docs", Kind: markdown.KindInline, Offset: 2, Line: 2},
+		}),
+		Entry("reference links", "[full][ref] [collapsed][] [ref]\n\n[ref]: https://ref.io\n[collapsed]: /collapsed", []markdown.Link{
+			{Destination: "https://ref.io", Kind: markdown.KindReference, Offset: 0, Line: 1},
+			{Destination: "/collapsed", Kind: markdown.KindReference, Offset: 12, Line: 1},
+			{Destination: "https://ref.io", Kind: markdown.KindReference, Offset: 26, Line: 1},
+		}),
+		Entry("images", "![logo](img/logo.png) ![ref][img]\n\n[img]: img/ref.png", []markdown.Link{
+			{Destination: "img/logo.png", Kind: markdown.KindImage, Offset: 0, Line: 1},
+			{Destination: "img/ref.png", Kind: markdown.KindImage, Offset: 22, Line: 1},
+		}),
+		Entry("nested image link", "[![badge](https://badge.svg)](https://ci.io)", []markdown.Link{
+			{Destination: "https://ci.io", Kind: markdown.KindInline, Offset: 0, Line: 1},
+			{Destination: "https://badge.svg", Kind: markdown.KindImage, Offset: 1, Line: 1},
+		}),
+		Entry("autolinks", "<https://a.io> and https://b.io/x\n<foo@bar.io>", []markdown.Link{
+			{Destination: "https://a.io", Kind: markdown.KindAutolink, Offset: 0, Line: 1},
+			{Destination: "https://b.io/x", Kind: markdown.KindAutolink, Offset: 19, Line: 1},
+			{Destination: "foo@bar.io", Kind: markdown.KindAutolink, Offset: 34, Line: 2},
+		}),
+		Entry("links in code are skipped", "`[a](b)`\n\n    [c](d)\n", nil),
+		Entry("embedded HTML", "<p>\n<a href=\"/a\">a</a> <img src=\"img/b.png\"/>\n</p>\n\nrow <a href=\"https://c.io\">c</a> <img src=\"data:image/png;base64,AAAA\"/>\n<!-- <a href=\"/d\"> -->", []markdown.Link{
+			{Destination: "/a", Kind: markdown.KindHTML, Offset: 4, Line: 2},
+			{Destination: "img/b.png", Kind: markdown.KindHTML, Offset: 23, Line: 2},
+			{Destination: "https://c.io", Kind: markdown.KindHTML, Offset: 56, Line: 5},
+		}),
+	)
+	It("ExtractImages", func() {
+		content := "![logo](img/logo.png) [docs](/docs)\n<p>\n<a href=\"/a\">a</a> <IMG src=\"img/b.png\"/> <imgx src=\"c.png\">\n</p>\n\n[![badge](https://badge.svg)](https://ci.io)"
+		Expect(markdown.ExtractImages([]byte(content))).To(Equal([]markdown.Link{
+			{Destination: "img/logo.png", Kind: markdown.KindImage, Offset: 0, Line: 1},
+			{Destination: "img/b.png", Kind: markdown.KindHTML, Offset: 59, Line: 3},
+			{Destination: "https://badge.svg", Kind: markdown.KindImage, Offset: 108, Line: 6},
+		}))
+	})
+	It("ExtractHTMLLinks", func() {
+		content := "<html><body>\n<a name=\"x\">x</a><a href=\"/a\">a</a>\n<img alt=\"b\" src=\"b.png\">\n<img src=\" DATA:image/png;base64,AAAA\"></body></html>"
+		Expect(markdown.ExtractHTMLLinks([]byte(content))).To(Equal([]markdown.Link{
+			{Destination: "/a", Kind: markdown.KindHTML, Offset: 30, Line: 2},
+			{Destination: "b.png", Kind: markdown.KindHTML, Offset: 49, Line: 3},
+		}))
+	})
+})
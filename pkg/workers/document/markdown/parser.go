@@ -40,3 +40,31 @@ func Parse(source []byte) (ast.Node, error) {
 	}
 	return doc, nil
 }
+
+// FirstHeading returns the text of the first level one heading in the document or empty string if there is none
+func FirstHeading(doc ast.Node, source []byte) string {
+	var heading string
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if h, ok := n.(*ast.Heading); ok && entering && h.Level == 1 {
+			heading = string(h.Text(source))
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return heading
+}
+
+// HeadingAnchors returns the link fragments of the headings in the document in document order,
+// repeated headings get unique fragments the same way GitHub generates them
+func HeadingAnchors(doc ast.Node, source []byte) []string {
+	var anchors []string
+	slugger := Slugger{}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if h, ok := n.(*ast.Heading); ok && entering {
+			anchors = append(anchors, slugger.Slug(string(h.Text(source))))
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return anchors
+}
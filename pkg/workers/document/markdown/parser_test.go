@@ -66,4 +66,25 @@ var _ = Describe("Parser", func() {
 			})
 		})
 	})
+	When("FirstHeading", func() {
+		It("returns empty string when there is no level one heading", func() {
+			Expect(markdown.FirstHeading(doc, []byte(md))).To(Equal(""))
+		})
+		Context("level one headings", func() {
+			BeforeEach(func() {
+				md = "## Heading level 2\n\n# First *heading*\n\n# Second heading\n"
+			})
+			It("returns the first one", func() {
+				Expect(markdown.FirstHeading(doc, []byte(md))).To(Equal("First heading"))
+			})
+		})
+	})
+	When("HeadingAnchors", func() {
+		BeforeEach(func() {
+			md = "# Guide\n\n## Install *now*\n\n```sh\n# not a heading\n```\n\nUsage\n-----\n\n## Install now\n"
+		})
+		It("returns the unique anchors of all headings", func() {
+			Expect(markdown.HeadingAnchors(doc, []byte(md))).To(Equal([]string{"guide", "install-now", "usage", "install-now-1"}))
+		})
+	})
 })
@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// ExtractSection returns the markdown section starting with the top-level heading matching
+// heading by text or link fragment and ending before the next heading of the same or higher level
+func ExtractSection(source []byte, heading string) ([]byte, error) {
+	doc, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	var section *ast.Heading
+	start, end := -1, len(source)
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		h, ok := n.(*ast.Heading)
+		if !ok || h.Lines().Len() == 0 {
+			continue
+		}
+		if section != nil && h.Level <= section.Level {
+			end = lineStart(source, h.Lines().At(0).Start)
+			break
+		}
+		if text := string(h.Text(source)); section == nil && (text == heading || Slugify(text) == heading) {
+			section = h
+			start = lineStart(source, h.Lines().At(0).Start)
+		}
+	}
+	if section == nil {
+		return nil, fmt.Errorf("no section with heading %s", heading)
+	}
+	return source[start:end], nil
+}
+
+// lineStart returns the offset of the beginning of the line containing offset
+func lineStart(source []byte, offset int) int {
+	return bytes.LastIndexByte(source[:offset], '\n') + 1
+}
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package markdown_test
+
+import (
+	"github.com/gardener/docforge/pkg/workers/document/markdown"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Section", func() {
+	md := "---\ntitle: test\n---\n# Guide\n\nIntro\n\n## Install\n\nRun it.\n\n```sh\n# not a heading\n```\n\n### Options\n\nFlags\n\nUsage\n-----\n\nCall it.\n\n# Appendix\n"
+	DescribeTable("extracting",
+		func(heading string, expected string) {
+			got, err := markdown.ExtractSection([]byte(md), heading)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(got)).To(Equal(expected))
+		},
+		Entry("middle section with subsections by text", "Install", "## Install\n\nRun it.\n\n```sh\n# not a heading\n```\n\n### Options\n\nFlags\n\n"),
+		Entry("setext heading by link fragment", "usage", "Usage\n-----\n\nCall it.\n\n"),
+		Entry("last section", "appendix", "# Appendix\n"),
+	)
+	It("fails for missing heading", func() {
+		_, err := markdown.ExtractSection([]byte(md), "missing")
+		Expect(err).To(MatchError("no section with heading missing"))
+	})
+})
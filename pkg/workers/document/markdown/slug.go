@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Slugify converts a heading text to a link fragment following GitHub's heading anchor rules:
+// the text is lowercased, everything except letters, numbers, '-' and '_' is removed
+// and spaces are replaced with '-'
+func Slugify(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// Slugger generates unique link fragments for the headings of a document.
+// Repeated headings are suffixed with -1, -2, etc. the same way GitHub does.
+type Slugger struct {
+	seen map[string]int
+}
+
+// Slug returns unique link fragment for a heading
+func (s *Slugger) Slug(heading string) string {
+	if s.seen == nil {
+		s.seen = map[string]int{}
+	}
+	slug := Slugify(heading)
+	unique := slug
+	for {
+		count, ok := s.seen[unique]
+		if !ok {
+			break
+		}
+		s.seen[unique] = count + 1
+		unique = fmt.Sprintf("%s-%d", slug, count+1)
+	}
+	s.seen[unique] = 0
+	return unique
+}
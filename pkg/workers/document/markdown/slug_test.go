@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package markdown_test
+
+import (
+	"github.com/gardener/docforge/pkg/workers/document/markdown"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Slug", func() {
+	DescribeTable("Slugify",
+		func(heading string, expected string) {
+			Expect(markdown.Slugify(heading)).To(Equal(expected))
+		},
+		Entry("lowercase and spaces", "Getting Started", "getting-started"),
+		Entry("punctuation", "What's new? (v1.2.3)", "whats-new-v123"),
+		Entry("hyphens and underscores", "snake_case - kebab-case", "snake_case---kebab-case"),
+		Entry("code spans", "The `docforge` command", "the-docforge-command"),
+		Entry("unicode", "Über Größe 日本語", "über-größe-日本語"),
+		Entry("emoji", "Release :rocket: 🚀", "release-rocket-"),
+	)
+
+	It("suffixes duplicate headings", func() {
+		s := markdown.Slugger{}
+		Expect(s.Slug("Usage")).To(Equal("usage"))
+		Expect(s.Slug("Usage")).To(Equal("usage-1"))
+		Expect(s.Slug("Usage 1")).To(Equal("usage-1-1"))
+		Expect(s.Slug("Usage")).To(Equal("usage-2"))
+	})
+})
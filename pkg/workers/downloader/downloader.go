@@ -17,6 +17,8 @@ import (
 	"sync"
 
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/githubhttpcache"
+	"github.com/gardener/docforge/pkg/workers/taskqueue"
 	"github.com/gardener/docforge/pkg/writers"
 	"k8s.io/klog/v2"
 )
@@ -25,14 +27,18 @@ import (
 type DownloadWorker struct {
 	registry repositoryhosts.Registry
 	writer   writers.Writer
+	// resume skips resources already written by a previous run with the content of their git blob
+	resume bool
 	// lock for accessing the downloadedResources map
 	mux sync.Mutex
 	// map with downloaded resources
 	downloadedResources map[string]struct{}
 }
 
-// NewDownloader creates new downloader
-func NewDownloader(registry repositoryhosts.Registry, writer writers.Writer) (*DownloadWorker, error) {
+// NewDownloader creates new downloader.
+// If resume is true resources that the writer reports as already written with the git blob SHA known
+// by the repository host are not downloaded again.
+func NewDownloader(registry repositoryhosts.Registry, writer writers.Writer, resume bool) (*DownloadWorker, error) {
 	if registry == nil || reflect.ValueOf(registry).IsNil() {
 		return nil, errors.New("invalid argument: reader is nil")
 	}
@@ -42,6 +48,7 @@ func NewDownloader(registry repositoryhosts.Registry, writer writers.Writer) (*D
 	return &DownloadWorker{
 		registry:            registry,
 		writer:              writer,
+		resume:              resume,
 		downloadedResources: make(map[string]struct{}),
 	}, nil
 }
@@ -57,19 +64,20 @@ func DownloadURLName(url *url.URL, document string) string {
 
 }
 
-// Download downloads source as target
+// Download downloads source as target. The returned errors are taskqueue.WorkerError classifying
+// GitHub API errors as transient or permanent.
 func (d *DownloadWorker) Download(ctx context.Context, source string, target string, document string) error {
 	if !d.shouldDownload(source) {
 		return nil
 	}
 	if err := d.download(ctx, source, target); err != nil {
-		dErr := fmt.Errorf("downloading %s as %s from document %s failed: %v", source, target, document, err)
-		if _, ok := err.(repositoryhosts.ErrResourceNotFound); ok {
+		dErr := fmt.Errorf("downloading %s as %s from document %s failed: %w", source, target, document, err)
+		if errors.Is(err, repositoryhosts.ErrNotFound) {
 			// for missing resources just log warning
 			klog.Warning(dErr.Error())
 			return nil
 		}
-		return dErr
+		return taskqueue.NewWorkerError(dErr, githubhttpcache.IsTransient)
 	}
 	return nil
 }
@@ -86,12 +94,20 @@ func (d *DownloadWorker) shouldDownload(Source string) bool {
 }
 
 func (d *DownloadWorker) download(ctx context.Context, Source string, Target string) error {
-	klog.V(6).Infof("downloading %s as %s\n", Source, Target)
-	// normal read
 	repoHost, err := d.registry.Get(Source)
 	if err != nil {
 		return err
 	}
+	if d.resume && d.written(repoHost, Source, Target) {
+		klog.V(6).Infof("skipping download of %s as %s is already written\n", Source, Target)
+		return nil
+	}
+	klog.V(6).Infof("downloading %s as %s\n", Source, Target)
+	if reader, ok := repoHost.(repositoryhosts.StreamReader); ok {
+		if writer, ok := d.writer.(writers.StreamWriter); ok {
+			return stream(ctx, reader, writer, Source, Target)
+		}
+	}
 	blob, err := repoHost.Read(ctx, Source)
 	if err != nil {
 		return err
@@ -101,3 +117,27 @@ func (d *DownloadWorker) download(ctx context.Context, Source string, Target str
 	}
 	return nil
 }
+
+// written checks if the writer already wrote Target with the content of the git blob of Source
+func (d *DownloadWorker) written(repoHost repositoryhosts.RepositoryHost, Source string, Target string) bool {
+	hasher, ok := repoHost.(repositoryhosts.BlobHasher)
+	if !ok {
+		return false
+	}
+	checker, ok := d.writer.(writers.BlobChecker)
+	if !ok {
+		return false
+	}
+	sha, ok := hasher.BlobSHA(Source)
+	return ok && checker.WrittenBlob(Target, "", sha)
+}
+
+// stream copies the resource content to the target without buffering it
+func stream(ctx context.Context, reader repositoryhosts.StreamReader, writer writers.StreamWriter, Source string, Target string) error {
+	r, err := reader.ReadStream(ctx, Source)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return writer.WriteStream(Target, "", r, nil)
+}
@@ -5,16 +5,26 @@
 package downloader_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha1"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts/repositoryhostsfakes"
 	"github.com/gardener/docforge/pkg/workers/downloader"
+	"github.com/gardener/docforge/pkg/workers/taskqueue"
+	"github.com/gardener/docforge/pkg/writers"
 	"github.com/gardener/docforge/pkg/writers/writersfakes"
+	"github.com/google/go-github/v43/github"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -56,7 +66,7 @@ var _ = Describe("Executing Download", func() {
 		document = "fake_document"
 	})
 	JustBeforeEach(func() {
-		worker, err = downloader.NewDownloader(registry, writer)
+		worker, err = downloader.NewDownloader(registry, writer, false)
 		Expect(worker).NotTo(BeNil())
 		Expect(err).NotTo(HaveOccurred())
 
@@ -90,6 +100,31 @@ var _ = Describe("Executing Download", func() {
 			Expect(err.Error()).To(ContainSubstring("fake_read_err"))
 		})
 	})
+	Context("read fails with a GitHub API error", func() {
+		statusErr := func(code int) error {
+			return &github.ErrorResponse{Response: &http.Response{StatusCode: code, Request: &http.Request{Method: http.MethodGet, URL: &url.URL{}}}}
+		}
+		Context("bad gateway", func() {
+			BeforeEach(func() {
+				repoHost.ReadReturns(nil, statusErr(http.StatusBadGateway))
+			})
+			It("fails with a transient error", func() {
+				var wErr *taskqueue.WorkerError
+				Expect(errors.As(err, &wErr)).To(BeTrue())
+				Expect(wErr.Transient).To(BeTrue())
+			})
+		})
+		Context("not found", func() {
+			BeforeEach(func() {
+				repoHost.ReadReturns(nil, statusErr(http.StatusNotFound))
+			})
+			It("fails with a permanent error", func() {
+				var wErr *taskqueue.WorkerError
+				Expect(errors.As(err, &wErr)).To(BeTrue())
+				Expect(wErr.Transient).To(BeFalse())
+			})
+		})
+	})
 	Context("read fails with resource not found", func() {
 		BeforeEach(func() {
 			repoHost.ReadReturns(nil, repositoryhosts.ErrResourceNotFound("fake_target"))
@@ -119,3 +154,122 @@ var _ = Describe("Executing Download", func() {
 		Expect(string(content)).To(Equal("content"))
 	})
 })
+
+// streamingRepositoryHost is a repository host that supports streaming reads
+type streamingRepositoryHost struct {
+	*repositoryhostsfakes.FakeRepositoryHost
+	content []byte
+}
+
+func (s *streamingRepositoryHost) ReadStream(_ context.Context, _ string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(s.content)), nil
+}
+
+var _ = Describe("Streaming Download", func() {
+	var (
+		dir      string
+		repoHost *streamingRepositoryHost
+		worker   *downloader.DownloadWorker
+	)
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "downloader")
+		Expect(err).NotTo(HaveOccurred())
+		repoHost = &streamingRepositoryHost{
+			FakeRepositoryHost: &repositoryhostsfakes.FakeRepositoryHost{},
+			content:            bytes.Repeat([]byte("0123456789abcdef"), 1<<16),
+		}
+		registry := &repositoryhostsfakes.FakeRegistry{}
+		registry.GetReturns(repoHost, nil)
+		worker, err = downloader.NewDownloader(registry, &writers.FSWriter{Root: dir}, false)
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+	It("streams the resource to the file", func() {
+		Expect(worker.Download(context.Background(), "repoHost://large.png", "large.png", "fake_document")).To(Succeed())
+		Expect(repoHost.ReadCallCount()).To(Equal(0))
+		content, err := os.ReadFile(filepath.Join(dir, "large.png"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes.Equal(content, repoHost.content)).To(BeTrue())
+	})
+})
+
+// blobHashingRepositoryHost is a repository host that knows the git blob SHAs of its resources
+type blobHashingRepositoryHost struct {
+	*repositoryhostsfakes.FakeRepositoryHost
+	shas map[string]string
+}
+
+func (b *blobHashingRepositoryHost) BlobSHA(resourceURL string) (string, bool) {
+	sha, ok := b.shas[resourceURL]
+	return sha, ok
+}
+
+// gitBlobSHA computes the SHA of content as a git blob object
+func gitBlobSHA(content string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("blob %d\x00%s", len(content), content))))
+}
+
+var _ = Describe("Resuming Download", func() {
+	var (
+		dir      string
+		repoHost *blobHashingRepositoryHost
+		writer   *writers.FSWriter
+		worker   *downloader.DownloadWorker
+	)
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "downloader")
+		Expect(err).NotTo(HaveOccurred())
+		// a.png is up to date, b.png was interrupted, c.png and d.png are outdated
+		Expect(os.WriteFile(filepath.Join(dir, "a.png"), []byte("content"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "b.png"+writers.PartFileSuffix), []byte("cont"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "c.png"), []byte("previous"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "d.png"), []byte("previous"), 0644)).To(Succeed())
+		repoHost = &blobHashingRepositoryHost{
+			FakeRepositoryHost: &repositoryhostsfakes.FakeRepositoryHost{},
+			shas: map[string]string{
+				"repoHost://a.png": gitBlobSHA("content"),
+				"repoHost://b.png": gitBlobSHA("content"),
+				"repoHost://c.png": gitBlobSHA("content"),
+			},
+		}
+		repoHost.ReadReturns([]byte("content"), nil)
+		writer = &writers.FSWriter{Root: dir}
+	})
+	JustBeforeEach(func() {
+		registry := &repositoryhostsfakes.FakeRegistry{}
+		registry.GetReturns(repoHost, nil)
+		var err error
+		worker, err = downloader.NewDownloader(registry, writer, true)
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+	It("downloads only the resources not written with the blob content", func() {
+		for _, name := range []string{"a.png", "b.png", "c.png", "d.png"} {
+			Expect(worker.Download(context.Background(), "repoHost://"+name, name, "fake_document")).To(Succeed())
+		}
+		Expect(repoHost.ReadCallCount()).To(Equal(3))
+		for i, name := range []string{"b.png", "c.png", "d.png"} {
+			_, source := repoHost.ReadArgsForCall(i)
+			Expect(source).To(Equal("repoHost://" + name))
+			content, err := os.ReadFile(filepath.Join(dir, name))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("content"))
+		}
+		Expect(filepath.Join(dir, "b.png"+writers.PartFileSuffix)).NotTo(BeAnExistingFile())
+	})
+	Context("with hashed names", func() {
+		BeforeEach(func() {
+			writer.HashNames = true
+		})
+		It("downloads the resources again", func() {
+			Expect(worker.Download(context.Background(), "repoHost://a.png", "a.png", "fake_document")).To(Succeed())
+			Expect(repoHost.ReadCallCount()).To(Equal(1))
+		})
+	})
+})
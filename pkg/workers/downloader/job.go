@@ -30,8 +30,8 @@ type downloadScheduler struct {
 }
 
 // New create a DownloadScheduler to schedule download resources
-func New(workerCount int, failFast bool, wg *sync.WaitGroup, registry repositoryhosts.Registry, writer writers.Writer) (Interface, taskqueue.QueueController, error) {
-	dWorker, err := NewDownloader(registry, writer)
+func New(workerCount int, failFast bool, wg *sync.WaitGroup, registry repositoryhosts.Registry, writer writers.Writer, resume bool) (Interface, taskqueue.QueueController, error) {
+	dWorker, err := NewDownloader(registry, writer, resume)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -62,7 +62,7 @@ func (w *Worker) WriteGithubInfo(ctx context.Context, node *manifest.Node) error
 			return err
 		}
 		if info, err = repoHost.ReadGitInfo(ctx, s); err != nil {
-			if _, ok := err.(repositoryhosts.ErrResourceNotFound); ok {
+			if errors.Is(err, repositoryhosts.ErrNotFound) {
 				klog.Warningf("reading GitHub info for %s fails: %v\n", s, err)
 				continue
 			}
@@ -6,6 +6,7 @@ package linkresolver
 
 import (
 	"cmp"
+	"errors"
 	"fmt"
 	"net/url"
 	"path"
@@ -34,8 +35,11 @@ type Interface interface {
 // LinkResolver represents link resolving nessesary objects
 type LinkResolver struct {
 	Repositoryhosts repositoryhosts.Registry
-	SourceToNode    map[string][]*manifest.Node
-	Hugo            hugo.Hugo
+	// Sources resolves the relative links of the document sources it read, e.g. from plain web URLs
+	// that links to are kept external. Repositoryhosts is used if it's nil
+	Sources      repositoryhosts.Registry
+	SourceToNode map[string][]*manifest.Node
+	Hugo         hugo.Hugo
 }
 
 // ResolveLink resolves link
@@ -58,12 +62,16 @@ func (l *LinkResolver) ResolveLink(link string, node *manifest.Node, source stri
 		}
 	} else {
 		// convert destination to absolute link
-		docHandler, err := l.Repositoryhosts.Get(source)
+		sources := l.Sources
+		if sources == nil {
+			sources = l.Repositoryhosts
+		}
+		docHandler, err := sources.Get(source)
 		if err != nil {
 			return "", false, fmt.Errorf("unexpected error - can't get a handler for already read content: %w", err)
 		}
 		if link, err = docHandler.ToAbsLink(source, link); err != nil {
-			if _, ok := err.(repositoryhosts.ErrResourceNotFound); !ok {
+			if !errors.Is(err, repositoryhosts.ErrNotFound) {
 				return "", false, err
 			}
 			klog.Warningf("failed to validate absolute link for %s from source %s: %v\n", link, source, err)
@@ -16,8 +16,10 @@ import (
 
 	"github.com/gardener/docforge/cmd/hugo"
 	"github.com/gardener/docforge/pkg/manifest"
+	"github.com/gardener/docforge/pkg/osfakes/httpclient/httpclientfakes"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts/repositoryhostsfakes"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/web"
 	"github.com/gardener/docforge/pkg/workers/linkresolver"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -138,4 +140,57 @@ var _ = Describe("Document link resolving", func() {
 		})
 	})
 
+	Context("#ResolveLink in documents from plain web URLs", func() {
+		var (
+			linkResolver linkresolver.LinkResolver
+			node         *manifest.Node
+			source       string
+		)
+		BeforeEach(func() {
+			source = "https://example.com/docs/guide.md"
+			node = &manifest.Node{Type: "file", FileType: manifest.FileType{File: "guide.md", Source: source}, Path: "docs"}
+			linkResolver = linkresolver.LinkResolver{
+				Repositoryhosts: repositoryhosts.NewRegistry(),
+				Sources:         repositoryhosts.NewRegistry(web.New(&httpclientfakes.FakeClient{})),
+				SourceToNode:    map[string][]*manifest.Node{source: {node}},
+			}
+		})
+		It("leaves external links untouched", func() {
+			newLink, validate, err := linkResolver.ResolveLink("https://example.com/docs/usage.md#flags", node, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newLink).To(Equal("https://example.com/docs/usage.md#flags"))
+			Expect(validate).To(BeTrue())
+		})
+		It("resolves relative links against the source", func() {
+			newLink, validate, err := linkResolver.ResolveLink("../img/logo.png", node, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newLink).To(Equal("https://example.com/img/logo.png"))
+			Expect(validate).To(BeTrue())
+		})
+	})
+
+	Context("#RelativeLinks", func() {
+		var (
+			node         *manifest.Node
+			sourceToNode map[string][]*manifest.Node
+		)
+		BeforeEach(func() {
+			node = &manifest.Node{Type: "file", FileType: manifest.FileType{File: "setup.md", Source: "https://github.com/gardener/docforge/blob/master/docs/setup.md"}, Path: "docs/guides"}
+			usage := &manifest.Node{Type: "file", FileType: manifest.FileType{File: "usage.md", Source: "https://github.com/gardener/docforge/blob/master/docs/usage.md"}, Path: "docs/guides"}
+			faq := &manifest.Node{Type: "file", FileType: manifest.FileType{File: "faq.md", Source: "https://github.com/gardener/docforge/blob/master/faq.md"}, Path: "docs"}
+			sourceToNode = map[string][]*manifest.Node{}
+			for _, n := range []*manifest.Node{node, usage, faq} {
+				sourceToNode[n.Source] = append(sourceToNode[n.Source], n)
+			}
+		})
+		It("rewrites links to included documents only", func() {
+			content := "See [usage](https://github.com/gardener/docforge/blob/master/docs/usage.md#flags), " +
+				"the [FAQ](https://github.com/gardener/docforge/raw/master/faq.md?plain=1), " +
+				"[Gardener](https://gardener.cloud/docs/) and [install](../install.md).\n"
+			got, err := linkresolver.RelativeLinks([]byte(content), node, sourceToNode)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(got)).To(Equal("See [usage](usage.md#flags), the [FAQ](../faq.md?plain=1), " +
+				"[Gardener](https://gardener.cloud/docs/) and [install](../install.md).\n"))
+		})
+	})
 })
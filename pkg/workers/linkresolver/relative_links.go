@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package linkresolver
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gardener/docforge/pkg/manifest"
+	"github.com/gardener/docforge/pkg/readers/resource"
+	"github.com/gardener/docforge/pkg/workers/document/markdown"
+)
+
+// RelativeLinks rewrites the absolute links in the markdown content of node that point to sources
+// of other documents in sourceToNode into links relative to node, all other links are left untouched
+func RelativeLinks(content []byte, node *manifest.Node, sourceToNode map[string][]*manifest.Node) ([]byte, error) {
+	doc, err := markdown.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	relativize := func(link string, _ bool) (string, error) {
+		return relativeLink(link, node, sourceToNode), nil
+	}
+	var b bytes.Buffer
+	rnd := markdown.NewLinkModifierRenderer(markdown.WithLinkResolver(relativize))
+	if err = rnd.Render(&b, content, doc); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// relativeLink returns the link relative to node of the nearest document with the link source
+// or the link itself if it isn't an absolute link to such document
+func relativeLink(link string, node *manifest.Node, sourceToNode map[string][]*manifest.Node) string {
+	linkURL, err := url.Parse(link)
+	if err != nil || !linkURL.IsAbs() {
+		return link
+	}
+	source := (&url.URL{Scheme: linkURL.Scheme, Host: linkURL.Host, Path: linkURL.Path}).String()
+	if resource.IsResourceURL(link) {
+		if r, err := resource.FromURL(linkURL); err == nil {
+			// raw links refer to the same document as the blob sources
+			if r.Type == "raw" {
+				r.Type = "blob"
+			}
+			source = r.String()
+		}
+	}
+	var nearest string
+	for _, n := range sourceToNode[source] {
+		if rel := node.RelativePath(n); nearest == "" || strings.Count(rel, "/") < strings.Count(nearest, "/") {
+			nearest = rel
+		}
+	}
+	if nearest == "" {
+		return link
+	}
+	if linkURL.ForceQuery || linkURL.RawQuery != "" {
+		nearest = fmt.Sprintf("%s?%s", nearest, linkURL.RawQuery)
+	}
+	if linkURL.Fragment != "" {
+		nearest = fmt.Sprintf("%s#%s", nearest, linkURL.Fragment)
+	}
+	return nearest
+}
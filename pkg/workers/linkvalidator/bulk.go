@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package linkvalidator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"k8s.io/klog/v2"
+)
+
+// ValidationResult is the result of checking a link
+type ValidationResult struct {
+	// Link is the checked link
+	Link string
+	// Err is the CheckLink error, nil if the link is valid
+	Err error
+	// Hint suggests a remediation for a broken link, e.g. where a not found resource was moved
+	Hint string
+}
+
+// CheckLinks checks links with CheckLink in parallel using workers goroutines and returns the
+// results in completion order. If ctx is canceled no further links are checked and the results
+// collected so far are returned once all workers have exited. The checks interrupted by the
+// cancellation have no results.
+func (v *ValidatorWorker) CheckLinks(ctx context.Context, links []string, workers int) []ValidationResult {
+	if workers < 1 {
+		workers = 1
+	}
+	pending := make(chan string)
+	results := make(chan ValidationResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range pending {
+				results <- v.checkResult(ctx, link)
+			}
+		}()
+	}
+	go func() {
+		defer close(pending)
+		for _, link := range links {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case pending <- link:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	var collected []ValidationResult
+	for result := range results {
+		if ctx.Err() != nil && (errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, context.DeadlineExceeded)) {
+			continue
+		}
+		collected = append(collected, result)
+	}
+	return collected
+}
+
+// checkResult checks a link and adds a move hint if the link is not found
+func (v *ValidatorWorker) checkResult(ctx context.Context, link string) ValidationResult {
+	linkURL, err := url.Parse(link)
+	if err != nil {
+		return ValidationResult{Link: link, Err: err}
+	}
+	resp, err := v.checkLink(ctx, linkURL)
+	result := ValidationResult{Link: link, Err: err}
+	if err != nil && v.MoveHints && resp != nil && resp.StatusCode == http.StatusNotFound {
+		result.Hint = v.moveHint(ctx, link)
+	}
+	return result
+}
+
+// moveHint returns a hint where a not found link was moved to or an empty string if it is unknown
+func (v *ValidatorWorker) moveHint(ctx context.Context, link string) string {
+	repoHost, err := v.repository.Get(link)
+	if err != nil {
+		return ""
+	}
+	tracker, ok := repoHost.(repositoryhosts.MoveTracker)
+	if !ok {
+		return ""
+	}
+	moved, err := tracker.MovedTo(ctx, link)
+	if err != nil {
+		klog.V(6).Infof("looking up where %s was moved failed: %v", link, err)
+		return ""
+	}
+	if moved == "" {
+		return ""
+	}
+	return fmt.Sprintf("moved to %s", moved)
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package linkvalidator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// ETagCache holds the ETag and Last-Modified response headers of validated links.
+// They are sent as If-None-Match and If-Modified-Since on subsequent validations,
+// so unchanged resources are answered with HTTP Status 304.
+type ETagCache struct {
+	path    string
+	entries map[string]ETagEntry
+	mux     sync.RWMutex
+}
+
+// ETagEntry holds the cache validators of a link
+type ETagEntry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// LoadETagCache loads ETagCache from a file. If the file doesn't exist, an empty cache is returned.
+func LoadETagCache(path string) (*ETagCache, error) {
+	c := &ETagCache{
+		path:    path,
+		entries: make(map[string]ETagEntry),
+	}
+	cnt, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("reading ETag cache %s fails: %w", path, err)
+	}
+	if err = json.Unmarshal(cnt, &c.entries); err != nil {
+		return nil, fmt.Errorf("parsing ETag cache %s fails: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the cache to the file it was loaded from
+func (c *ETagCache) Save() error {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	cnt, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(c.path, cnt, 0644); err != nil {
+		return fmt.Errorf("writing ETag cache %s fails: %w", c.path, err)
+	}
+	return nil
+}
+
+// setConditionalHeaders sets If-None-Match and If-Modified-Since request headers for cached links
+func (c *ETagCache) setConditionalHeaders(req *http.Request, link string) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	e, ok := c.entries[link]
+	if !ok {
+		return
+	}
+	if e.ETag != "" {
+		req.Header.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		req.Header.Set("If-Modified-Since", e.LastModified)
+	}
+}
+
+// store records the ETag and Last-Modified headers of a successful response
+func (c *ETagCache) store(link string, resp *http.Response) {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return
+	}
+	e := ETagEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if e.ETag == "" && e.LastModified == "" {
+		return
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.entries[link] = e
+}
@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package linkvalidator
+
+import (
+	"context"
+	"time"
+)
+
+func (v *ValidatorWorker) SetClock(sleep func(ctx context.Context, d time.Duration) error, random func() float64) {
+	v.sleepFunc = sleep
+	v.randomFunc = random
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package linkvalidator
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gardener/docforge/pkg/manifest"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/workers/document/markdown"
+)
+
+// ExternalLink is an absolute http(s) link referenced by document nodes
+type ExternalLink struct {
+	// URL of the link
+	URL string
+	// Nodes are the paths of the nodes referencing the link
+	Nodes []string
+}
+
+// ExternalLinks reads the sources of the document nodes and returns the unique
+// absolute http(s) links they reference, sorted by URL
+func ExternalLinks(ctx context.Context, nodes []*manifest.Node, registry repositoryhosts.Registry) ([]ExternalLink, error) {
+	referrers := map[string]map[string]struct{}{}
+	for _, node := range nodes {
+		sources := node.MultiSource
+		if len(node.Source) > 0 {
+			sources = append([]string{node.Source}, sources...)
+		}
+		for _, source := range sources {
+			// a fragment selects a line range or a section of the source
+			source, section, _ := strings.Cut(source, "#")
+			repoHost, err := registry.Get(source)
+			if err != nil {
+				return nil, err
+			}
+			content, err := repoHost.Read(ctx, source)
+			if err != nil {
+				return nil, fmt.Errorf("reading source %s from node %s failed: %w", source, node.NodePath(), err)
+			}
+			if first, last, ok := markdown.ParseLineRange(section); ok {
+				content, err = markdown.ExtractLines(content, first, last)
+			} else if section != "" {
+				content, err = markdown.ExtractSection(content, section)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("selecting source %s#%s from node %s failed: %w", source, section, node.NodePath(), err)
+			}
+			for _, link := range markdown.ExtractLinks(content) {
+				u, err := url.Parse(link.Destination)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+					continue
+				}
+				if referrers[link.Destination] == nil {
+					referrers[link.Destination] = map[string]struct{}{}
+				}
+				referrers[link.Destination][node.NodePath()] = struct{}{}
+			}
+		}
+	}
+	links := make([]ExternalLink, 0, len(referrers))
+	for link, paths := range referrers {
+		l := ExternalLink{URL: link}
+		for p := range paths {
+			l.Nodes = append(l.Nodes, p)
+		}
+		sort.Strings(l.Nodes)
+		links = append(links, l)
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].URL < links[j].URL })
+	return links, nil
+}
+
+// ImageAsset is an image referenced by a document node
+type ImageAsset struct {
+	// URL is the absolute location of the image
+	URL string
+	// External is true for images referenced with absolute links, which are not assets
+	// of the source repositories
+	External bool
+}
+
+// ImageAssets reads the sources of a document node and returns the unique images they reference with
+// markdown or HTML in document order. Relative image links are resolved against their source.
+func ImageAssets(ctx context.Context, node *manifest.Node, registry repositoryhosts.Registry) ([]ImageAsset, error) {
+	sources := node.MultiSource
+	if len(node.Source) > 0 {
+		sources = append([]string{node.Source}, sources...)
+	}
+	var assets []ImageAsset
+	seen := map[string]bool{}
+	for _, source := range sources {
+		// a fragment selects a section of the source
+		source, _, _ = strings.Cut(source, "#")
+		repoHost, err := registry.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		content, err := repoHost.Read(ctx, source)
+		if err != nil {
+			return nil, fmt.Errorf("reading source %s from node %s failed: %w", source, node.NodePath(), err)
+		}
+		for _, image := range markdown.ExtractImages(content) {
+			u, err := url.Parse(image.Destination)
+			if err != nil {
+				return nil, fmt.Errorf("invalid image %s in source %s: %w", image.Destination, source, err)
+			}
+			asset := ImageAsset{URL: image.Destination, External: u.IsAbs()}
+			if !asset.External {
+				if asset.URL, err = repoHost.ToAbsLink(source, image.Destination); err != nil {
+					return nil, fmt.Errorf("resolving image %s in source %s failed: %w", image.Destination, source, err)
+				}
+			}
+			if !seen[asset.URL] {
+				seen[asset.URL] = true
+				assets = append(assets, asset)
+			}
+		}
+	}
+	return assets, nil
+}
@@ -6,6 +6,7 @@ package linkvalidator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -23,6 +24,10 @@ type Interface interface {
 	// ValidateLink checks if the link URL is available in a separate goroutine
 	// returns true if the task was added for processing, false if it was skipped
 	ValidateLink(linkDestination, contentSourcePath string) bool
+	// ValidateImage checks if the image URL is available in a separate goroutine,
+	// applying image specific checks if they are enabled
+	// returns true if the task was added for processing, false if it was skipped
+	ValidateImage(linkDestination, contentSourcePath string) bool
 }
 
 type validator struct {
@@ -36,6 +41,15 @@ func New(workerCount int, failFast bool, wg *sync.WaitGroup, registry repository
 	if err != nil {
 		return nil, nil, err
 	}
+	return NewFromWorker(workerCount, failFast, wg, vWorker)
+}
+
+// NewFromWorker creates new Validator running a configured ValidatorWorker,
+// e.g. one with caches loaded from previous runs
+func NewFromWorker(workerCount int, failFast bool, wg *sync.WaitGroup, vWorker *ValidatorWorker) (Interface, taskqueue.QueueController, error) {
+	if vWorker == nil {
+		return nil, nil, errors.New("invalid argument: validator worker is nil")
+	}
 	queue, err := taskqueue.New("Validator", workerCount, vWorker.execute, failFast, wg)
 	if err != nil {
 		return nil, nil, err
@@ -48,10 +62,21 @@ func New(workerCount int, failFast bool, wg *sync.WaitGroup, registry repository
 }
 
 func (v *validator) ValidateLink(linkDestination, contentSourcePath string) bool {
-	vTask := &validationTask{
+	return v.addTask(&validationTask{
 		LinkDestination:   linkDestination,
 		ContentSourcePath: contentSourcePath,
-	}
+	})
+}
+
+func (v *validator) ValidateImage(linkDestination, contentSourcePath string) bool {
+	return v.addTask(&validationTask{
+		LinkDestination:   linkDestination,
+		ContentSourcePath: contentSourcePath,
+		Image:             true,
+	})
+}
+
+func (v *validator) addTask(vTask *validationTask) bool {
 	added := v.queue.AddTask(vTask)
 	if !added {
 		klog.Warningf("link validation failed for task %v\n", vTask)
@@ -63,6 +88,7 @@ func (v *validator) ValidateLink(linkDestination, contentSourcePath string) bool
 type validationTask struct {
 	LinkDestination   string
 	ContentSourcePath string
+	Image             bool
 }
 
 // Validate checks if validationTask.LinkUrl is available and if it cannot be reached, a warning is logged
@@ -71,5 +97,8 @@ func (v *ValidatorWorker) execute(ctx context.Context, task interface{}) error {
 	if !ok {
 		return fmt.Errorf("incorrect validation task: %T", task)
 	}
+	if vTask.Image {
+		return v.ValidateImage(ctx, vTask.LinkDestination, vTask.ContentSourcePath)
+	}
 	return v.Validate(ctx, vTask.LinkDestination, vTask.ContentSourcePath)
 }
@@ -11,6 +11,18 @@ import (
 )
 
 type FakeInterface struct {
+	ValidateImageStub        func(string, string) bool
+	validateImageMutex       sync.RWMutex
+	validateImageArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	validateImageReturns struct {
+		result1 bool
+	}
+	validateImageReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	ValidateLinkStub        func(string, string) bool
 	validateLinkMutex       sync.RWMutex
 	validateLinkArgsForCall []struct {
@@ -27,6 +39,68 @@ type FakeInterface struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeInterface) ValidateImage(arg1 string, arg2 string) bool {
+	fake.validateImageMutex.Lock()
+	ret, specificReturn := fake.validateImageReturnsOnCall[len(fake.validateImageArgsForCall)]
+	fake.validateImageArgsForCall = append(fake.validateImageArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ValidateImageStub
+	fakeReturns := fake.validateImageReturns
+	fake.recordInvocation("ValidateImage", []interface{}{arg1, arg2})
+	fake.validateImageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeInterface) ValidateImageCallCount() int {
+	fake.validateImageMutex.RLock()
+	defer fake.validateImageMutex.RUnlock()
+	return len(fake.validateImageArgsForCall)
+}
+
+func (fake *FakeInterface) ValidateImageCalls(stub func(string, string) bool) {
+	fake.validateImageMutex.Lock()
+	defer fake.validateImageMutex.Unlock()
+	fake.ValidateImageStub = stub
+}
+
+func (fake *FakeInterface) ValidateImageArgsForCall(i int) (string, string) {
+	fake.validateImageMutex.RLock()
+	defer fake.validateImageMutex.RUnlock()
+	argsForCall := fake.validateImageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInterface) ValidateImageReturns(result1 bool) {
+	fake.validateImageMutex.Lock()
+	defer fake.validateImageMutex.Unlock()
+	fake.ValidateImageStub = nil
+	fake.validateImageReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeInterface) ValidateImageReturnsOnCall(i int, result1 bool) {
+	fake.validateImageMutex.Lock()
+	defer fake.validateImageMutex.Unlock()
+	fake.ValidateImageStub = nil
+	if fake.validateImageReturnsOnCall == nil {
+		fake.validateImageReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.validateImageReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeInterface) ValidateLink(arg1 string, arg2 string) bool {
 	fake.validateLinkMutex.Lock()
 	ret, specificReturn := fake.validateLinkReturnsOnCall[len(fake.validateLinkArgsForCall)]
@@ -92,6 +166,8 @@ func (fake *FakeInterface) ValidateLinkReturnsOnCall(i int, result1 bool) {
 func (fake *FakeInterface) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.validateImageMutex.RLock()
+	defer fake.validateImageMutex.RUnlock()
 	fake.validateLinkMutex.RLock()
 	defer fake.validateLinkMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package linkvalidator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ErrRobotsDisallowed is wrapped by the errors of links that are not checked
+// because the robots.txt of their host disallows them
+var ErrRobotsDisallowed = errors.New("disallowed by robots.txt")
+
+// robotsFetchTimeout bounds the robots.txt fetches, which are not canceled with the link check starting them
+const robotsFetchTimeout = 30 * time.Second
+
+// RobotsCache holds the robots.txt rules of the link hosts for a user agent.
+// The robots.txt of each host is fetched once, a missing or unreachable robots.txt allows all links.
+// The fetches failing with an error or a server error status are not cached and repeated for the next link.
+type RobotsCache struct {
+	userAgent string
+	hosts     map[string]*robotsEntry
+	mux       sync.Mutex
+}
+
+type robotsEntry struct {
+	mux    sync.Mutex
+	loaded bool
+	rules  []robotsRule
+}
+
+// robotsRule is an allow or disallow rule of a robots.txt group
+type robotsRule struct {
+	allow   bool
+	length  int
+	pattern *regexp.Regexp
+}
+
+// NewRobotsCache creates a RobotsCache applying the robots.txt rules for userAgent,
+// the product token of userAgent is matched with the user-agent lines of robots.txt files
+func NewRobotsCache(userAgent string) *RobotsCache {
+	return &RobotsCache{
+		userAgent: userAgent,
+		hosts:     make(map[string]*robotsEntry),
+	}
+}
+
+// allowed checks if the robots.txt of the link host allows the link, fetching it with v if needed
+func (c *RobotsCache) allowed(ctx context.Context, v *ValidatorWorker, linkURL *url.URL) bool {
+	host := linkURL.Scheme + "://" + linkURL.Host
+	c.mux.Lock()
+	entry, ok := c.hosts[host]
+	if !ok {
+		entry = &robotsEntry{}
+		c.hosts[host] = entry
+	}
+	c.mux.Unlock()
+	entry.mux.Lock()
+	if !entry.loaded {
+		// the rules are cached for all links of the host, the fetch doesn't depend on this link check
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), robotsFetchTimeout)
+		var content []byte
+		content, entry.loaded = c.fetch(fetchCtx, v, host+"/robots.txt")
+		cancel()
+		entry.rules = parseRobots(content, c.userAgent)
+	}
+	rules := entry.rules
+	entry.mux.Unlock()
+	p := linkURL.EscapedPath()
+	if p == "" {
+		p = "/"
+	}
+	if linkURL.RawQuery != "" {
+		p += "?" + linkURL.RawQuery
+	}
+	var match *robotsRule
+	for i, rule := range rules {
+		if !rule.pattern.MatchString(p) {
+			continue
+		}
+		// the longest rule wins, allow wins ties
+		if match == nil || rule.length > match.length || rule.length == match.length && rule.allow {
+			match = &rules[i]
+		}
+	}
+	return match == nil || match.allow
+}
+
+// fetch returns the content of a robots.txt or nil if it can't be fetched. It returns false
+// if the fetch failed and should be repeated, a missing robots.txt is not a failure.
+func (c *RobotsCache) fetch(ctx context.Context, v *ValidatorWorker, robotsURL string) ([]byte, bool) {
+	req, err := v.newRequest(ctx, http.MethodGet, robotsURL)
+	if err != nil {
+		return nil, true
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	// the body is read, http.Transport negotiates the encoding it decodes
+	req.Header.Del("Accept-Encoding")
+	resp, err := v.client(robotsURL).Do(req)
+	if err != nil {
+		klog.Warningf("fetching %s failed, the link is allowed: %v\n", robotsURL, err)
+		return nil, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		klog.Warningf("fetching %s failed with HTTP status %d, the link is allowed\n", robotsURL, resp.StatusCode)
+		return nil, false
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, true
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, 512*1024))
+	if err != nil {
+		klog.Warningf("reading %s failed, the link is allowed: %v\n", robotsURL, err)
+		return nil, false
+	}
+	return content, true
+}
+
+// parseRobots returns the rules of the robots.txt groups for the product token of userAgent,
+// or the rules of the `*` groups if there is none for it
+func parseRobots(content []byte, userAgent string) []robotsRule {
+	token, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(userAgent)), "/")
+	var agentRules, defaultRules []robotsRule
+	var agents []string
+	inRules, agentGroup := false, false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agent := strings.ToLower(value)
+			agents = append(agents, agent)
+			agentGroup = agentGroup || agent == token
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue
+			}
+			rule := robotsRule{allow: key == "allow", length: len(value), pattern: robotsPattern(value)}
+			for _, agent := range agents {
+				switch agent {
+				case token:
+					agentRules = append(agentRules, rule)
+				case "*":
+					defaultRules = append(defaultRules, rule)
+				}
+			}
+		}
+	}
+	if agentGroup {
+		return agentRules
+	}
+	return defaultRules
+}
+
+// robotsPattern compiles a robots.txt path pattern, `*` matches any characters and a trailing `$` the path end
+func robotsPattern(value string) *regexp.Regexp {
+	anchored := strings.HasSuffix(value, "$")
+	value = strings.TrimSuffix(value, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
@@ -6,12 +6,17 @@ package linkvalidator
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"math/rand"
 	"net/http"
 	"net/url"
+	"os"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,13 +24,157 @@ import (
 
 	"github.com/gardener/docforge/pkg/osfakes/httpclient"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/workers/document/markdown"
 	"k8s.io/klog/v2"
 )
 
+// DefaultHeaders are the headers set on validation requests by default,
+// some hosts reject requests without Accept or a realistic User-Agent.
+var DefaultHeaders = map[string]string{
+	"Accept":     "*/*",
+	"User-Agent": "docforge",
+}
+
+// DefaultAcceptEncoding is set on HEAD and single byte range GET validation requests without
+// Accept-Encoding, some CDNs reject them without it. Their responses have no body to decode.
+// The GET requests reading the body leave it to http.Transport, which decodes gzip responses
+// transparently only if it negotiated the encoding itself.
+const DefaultAcceptEncoding = "gzip, deflate, br"
+
 // ValidatorWorker holds nessesary objects ti validate URl
 type ValidatorWorker struct {
+	// Headers are set on each HEAD and GET validation request
+	Headers map[string]string
+	// ETags enables conditional validation requests if set
+	ETags *ETagCache
+	// Images enables image specific validation if set
+	Images *ImageValidation
+	// GETHosts are hosts rejecting HEAD requests, links to them are validated with a
+	// single byte range GET request instead
+	GETHosts []string
+	// Credentials authenticate the validation requests to hosts by host name
+	Credentials map[string]Credentials
+	// DedupKey selects the link components that distinguish validated links, by default
+	// links differing only by query or fragment are validated once
+	DedupKey DedupKey
+	// HostDedupKeys override DedupKey by host name
+	HostDedupKeys map[string]DedupKey
+	// HealthChecks distinguish broken links from temporarily down hosts by host name
+	HealthChecks map[string]HealthCheck
+	// Redirects overrides the redirect policy of the HTTP clients if set
+	Redirects *RedirectPolicy
+	// MoveHints enables looking up where links not found were moved to on repository hosts
+	// implementing repositoryhosts.MoveTracker, reported as ValidationResult hints
+	MoveHints bool
+	// Backoff configures the retries of rate limited requests, DefaultBackoff is used if nil
+	Backoff *Backoff
+	// Robots enables skipping links disallowed by the robots.txt of their hosts if set
+	Robots *RobotsCache
+	// Anchors enables checking that the fragments of links to markdown files on repository hosts
+	// are heading anchors of the files, the files are fetched once from their raw format links.
+	// Set DedupKey.Fragment to check all anchors of a file and not only the first one validated.
+	Anchors    bool
+	anchors    sync.Map
 	repository repositoryhosts.Registry
 	validated  *linkSet
+	reported   *linkSet
+	sleepFunc  func(ctx context.Context, d time.Duration) error
+	randomFunc func() float64
+}
+
+// ImageValidation configures the validation of image links
+type ImageValidation struct {
+	// MaxSize is the maximum image size in bytes, 0 means no limit
+	MaxSize int64
+}
+
+// RedirectPolicy configures how validation requests follow redirects. It applies to
+// *http.Client clients only, other clients are used with their own redirect handling.
+type RedirectPolicy struct {
+	// Disabled stops following redirects, a redirect response is a valid link response then
+	Disabled bool
+	// Max is the maximum number of redirects to follow, 0 means the client default of 10
+	Max int
+}
+
+// checkRedirect implements http.Client#CheckRedirect
+func (r *RedirectPolicy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if r.Disabled {
+		return http.ErrUseLastResponse
+	}
+	max := r.Max
+	if max <= 0 {
+		max = 10
+	}
+	if len(via) > max {
+		return fmt.Errorf("stopped after %d redirects", max)
+	}
+	return nil
+}
+
+// ErrAnchorNotFound is wrapped by the errors for links to markdown files without a heading matching the link fragment
+var ErrAnchorNotFound = errors.New("anchor not found")
+
+// errAnchorsNotVerified is returned when the content of a markdown file can't be fetched to check its anchors
+var errAnchorsNotVerified = errors.New("anchors can't be verified")
+
+// ErrHostDown is wrapped by the errors for links to hosts failing their health check
+var ErrHostDown = errors.New("host is temporarily down")
+
+// HealthCheck configures the health check of a host. After a link to the host fails the
+// health path is requested and the link is checked again only if the host is healthy.
+type HealthCheck struct {
+	// Path of the health check request, the host root if empty
+	Path string
+	// Delay before the health check
+	Delay time.Duration
+}
+
+// Backoff configures the delays before retrying requests rejected with HTTP Status 429.
+// A Retry-After header of up to 5 minutes overrides the delay.
+type Backoff struct {
+	// Intervals are the base delays of the retries, there is a retry for each interval
+	Intervals []time.Duration
+	// Jitter is the fraction of each interval that is randomized, the delay is in
+	// [(1-Jitter)*interval, interval]. 1 is full jitter and 0 disables jitter.
+	Jitter float64
+}
+
+// DefaultBackoff retries rate limited requests after 1, 5 and 10 seconds with full jitter
+var DefaultBackoff = Backoff{
+	Intervals: []time.Duration{time.Second, 5 * time.Second, 10 * time.Second},
+	Jitter:    1,
+}
+
+// delay returns the randomized delay of a retry for random in [0, 1)
+func (b *Backoff) delay(retry int, random float64) time.Duration {
+	interval := b.Intervals[retry]
+	jitter := math.Min(math.Max(b.Jitter, 0), 1)
+	return interval - time.Duration(jitter*random*float64(interval))
+}
+
+// DedupKey selects the link components besides scheme, host and path that distinguish validated links
+type DedupKey struct {
+	Query    bool
+	Fragment bool
+}
+
+// Credentials for validation requests, Token is sent as bearer token
+// and takes precedence over Username and Password sent with basic authentication
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// String redacts the credentials so that they are never logged
+func (c Credentials) String() string {
+	return "[redacted]"
+}
+
+// GoString redacts the credentials so that they are never logged
+func (c Credentials) GoString() string {
+	return c.String()
 }
 
 // NewValidatorWorker creates new ValidatorWorker
@@ -33,106 +182,484 @@ func NewValidatorWorker(repository repositoryhosts.Registry) (*ValidatorWorker,
 	if repository == nil || reflect.ValueOf(repository).IsNil() {
 		return nil, errors.New("invalid argument: repositoryhosts is nil")
 	}
+	headers := make(map[string]string, len(DefaultHeaders))
+	for k, v := range DefaultHeaders {
+		headers[k] = v
+	}
 	return &ValidatorWorker{
-		repository,
-		&linkSet{
-			set: make(map[string]struct{}),
+		Headers:    headers,
+		repository: repository,
+		validated: &linkSet{
+			set: make(map[string]time.Time),
+		},
+		reported: &linkSet{
+			set: make(map[string]time.Time),
 		},
 	}, nil
 }
 
 // Validate validates a link
 func (v *ValidatorWorker) Validate(ctx context.Context, LinkDestination string, ContentSourcePath string) error {
+	LinkURL, unifiedURL, err := v.toValidate(LinkDestination, ContentSourcePath)
+	if err != nil || LinkURL == nil {
+		return err
+	}
+	resp, err := v.checkLink(ctx, LinkURL)
+	if errors.Is(err, ErrHostDown) {
+		// not marked as validated, the link is validated again when referenced next time
+		klog.Warningf("skipped validation of absolute link for %s from source %s: %v\n",
+			LinkDestination, ContentSourcePath, err)
+		return nil
+	}
+	if errors.Is(err, ErrRobotsDisallowed) {
+		klog.V(6).Infof("skipped validation of absolute link for %s from source %s: %v\n",
+			LinkDestination, ContentSourcePath, err)
+		v.reported.add(unifiedURL)
+		return nil
+	}
+	if err != nil {
+		klog.Warningf("failed to validate absolute link for %s from source %s: %v\n",
+			LinkDestination, ContentSourcePath, err)
+		// reported once per run but not persisted, the link is validated again by the next run
+		v.reported.add(unifiedURL)
+		return nil
+	}
+	if resp != nil && v.ETags != nil {
+		v.ETags.store(LinkURL.String(), resp)
+	}
+	v.validated.add(unifiedURL)
+	return nil
+}
+
+// CheckLink returns an error if an absolute link is broken. If a health check is configured for the
+// link host, a failed link is checked again after the host is found healthy, otherwise the returned
+// error wraps ErrHostDown. Links disallowed by robots.txt are not requested and the returned error
+// wraps ErrRobotsDisallowed.
+func (v *ValidatorWorker) CheckLink(ctx context.Context, link string) error {
+	linkURL, err := url.Parse(link)
+	if err != nil {
+		return err
+	}
+	_, err = v.checkLink(ctx, linkURL)
+	return err
+}
+
+// checkLink requests the link unless robots.txt disallows it and runs the health check of the link host on failure
+func (v *ValidatorWorker) checkLink(ctx context.Context, linkURL *url.URL) (*http.Response, error) {
+	if v.Robots != nil && !v.Robots.allowed(ctx, v, linkURL) {
+		return nil, fmt.Errorf("%w for user agent %s", ErrRobotsDisallowed, v.Robots.userAgent)
+	}
+	resp, err := v.request(ctx, linkURL)
+	hc, ok := v.healthCheck(linkURL.Hostname())
+	if err == nil && v.Anchors {
+		return resp, v.checkAnchor(ctx, linkURL)
+	}
+	if err == nil || !ok {
+		return resp, err
+	}
+	select {
+	case <-time.After(hc.Delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if herr := v.checkHealth(ctx, linkURL, hc); herr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHostDown, herr)
+	}
+	return v.request(ctx, linkURL)
+}
+
+// checkAnchor fetches the raw content of a link to a markdown file on a repository host and checks
+// that the link fragment is one of its heading anchors. Other links and line fragments are not checked.
+func (v *ValidatorWorker) checkAnchor(ctx context.Context, linkURL *url.URL) error {
+	fragment := strings.TrimPrefix(linkURL.Fragment, "user-content-")
+	if fragment == "" || !strings.HasSuffix(strings.ToLower(linkURL.Path), ".md") {
+		return nil
+	}
+	if _, _, ok := markdown.ParseLineRange(fragment); ok {
+		return nil
+	}
+	docURL := *linkURL
+	docURL.Fragment, docURL.RawFragment = "", ""
+	repoHost, err := v.repository.Get(docURL.String())
+	if err != nil {
+		// not a link to a repository host
+		return nil
+	}
+	rawLink, err := repoHost.GetRawFormatLink(docURL.String())
+	if err != nil {
+		return err
+	}
+	anchors, ok := v.anchors.Load(rawLink)
+	if !ok {
+		anchors, err = v.fetchAnchors(ctx, rawLink)
+		if errors.Is(err, errAnchorsNotVerified) {
+			klog.V(6).Infof("skipped anchor validation of %s: %v\n", linkURL, err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		v.anchors.Store(rawLink, anchors)
+	}
+	if !slices.Contains(anchors.([]string), fragment) {
+		return fmt.Errorf("%w: #%s", ErrAnchorNotFound, linkURL.Fragment)
+	}
+	return nil
+}
+
+// fetchAnchors returns the heading anchors of the markdown file at rawLink. The content is fetched
+// with a plain GET, without conditional headers that may get no content and without encoding headers
+// that stop http.Transport from decompressing it. The returned error wraps errAnchorsNotVerified
+// for HTTP Status 304.
+func (v *ValidatorWorker) fetchAnchors(ctx context.Context, rawLink string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawLink, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare GET anchor validation request: %v", err)
+	}
+	v.setHeaders(req)
+	for _, h := range []string{"Accept-Encoding", "If-None-Match", "If-Modified-Since", "Range"} {
+		req.Header.Del(h)
+	}
+	resp, err := v.client(rawLink).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s for anchor validation: %v", rawLink, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, fmt.Errorf("fetching %s: %w", rawLink, errAnchorsNotVerified)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("fetching %s for anchor validation: HTTP Status %s", rawLink, resp.Status)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s for anchor validation: %v", rawLink, err)
+	}
+	doc, err := markdown.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	return markdown.HeadingAnchors(doc, content), nil
+}
+
+// request tries HEAD or a single byte range GET on hosts rejecting HEAD and retries with GET
+// on error status codes different from authorization errors. The response is returned together
+// with the error for the final error status code.
+func (v *ValidatorWorker) request(ctx context.Context, linkURL *url.URL) (*http.Response, error) {
 	var (
 		req  *http.Request
 		resp *http.Response
+		err  error
 	)
+	link := linkURL.String()
+	client := v.client(link)
+	if v.rejectsHEAD(linkURL.Hostname()) {
+		if req, err = v.newRequest(ctx, http.MethodGet, link); err != nil {
+			return nil, fmt.Errorf("failed to prepare GET validation request: %v", err)
+		}
+		req.Header.Set("Range", "bytes=0-0")
+	} else if req, err = v.newRequest(ctx, http.MethodHead, link); err != nil {
+		return nil, fmt.Errorf("failed to prepare HEAD validation request: %v", err)
+	}
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", DefaultAcceptEncoding)
+	}
+	if resp, err = v.doValidation(req, client); err != nil || !failed(resp) {
+		return resp, err
+	}
+	if req, err = v.newRequest(ctx, http.MethodGet, link); err != nil {
+		return nil, fmt.Errorf("failed to prepare GET validation request: %v", err)
+	}
+	if resp, err = v.doValidation(req, client); err != nil || !failed(resp) {
+		return resp, err
+	}
+	return resp, fmt.Errorf("HTTP Status %s", resp.Status)
+}
+
+// failed checks if the response status is an error different from authorization errors
+func failed(resp *http.Response) bool {
+	return resp.StatusCode >= 400 && resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusUnauthorized
+}
+
+// healthCheck returns the HealthCheck for host
+func (v *ValidatorWorker) healthCheck(host string) (HealthCheck, bool) {
+	for h, hc := range v.HealthChecks {
+		if strings.EqualFold(h, host) {
+			return hc, true
+		}
+	}
+	return HealthCheck{}, false
+}
+
+// checkHealth requests the health path of the link host, the host is healthy unless
+// the request fails or the response status is a server error
+func (v *ValidatorWorker) checkHealth(ctx context.Context, linkURL *url.URL, hc HealthCheck) error {
+	healthURL := &url.URL{Scheme: linkURL.Scheme, Host: linkURL.Host, Path: hc.Path}
+	if healthURL.Path == "" {
+		healthURL.Path = "/"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	v.setHeaders(req)
+	resp, err := v.doValidation(req, v.client(healthURL.String()))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("health check %s: HTTP Status %s", healthURL, resp.Status)
+	}
+	return nil
+}
+
+// ValidateImage validates an image link with CheckImage if image validation is enabled,
+// otherwise it's validated as any other link
+func (v *ValidatorWorker) ValidateImage(ctx context.Context, LinkDestination string, ContentSourcePath string) error {
+	if v.Images == nil {
+		return v.Validate(ctx, LinkDestination, ContentSourcePath)
+	}
+	LinkURL, unifiedURL, err := v.toValidate(LinkDestination, ContentSourcePath)
+	if err != nil || LinkURL == nil {
+		return err
+	}
+	if err = v.CheckImage(ctx, LinkURL.String()); err != nil {
+		klog.Warningf("failed to validate image %s from source %s: %v\n",
+			LinkDestination, ContentSourcePath, err)
+		v.reported.add(unifiedURL)
+		return nil
+	}
+	v.validated.add(unifiedURL)
+	return nil
+}
+
+// CheckImage issues a GET request and returns an error if the image link is not available,
+// its Content-Type is not an image or its Content-Length exceeds the configured MaxSize
+func (v *ValidatorWorker) CheckImage(ctx context.Context, link string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return err
+	}
+	v.setHeaders(req)
+	resp, err := v.doValidation(req, v.client(link))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("HTTP Status %s", resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("unexpected Content-Type %q", contentType)
+	}
+	if v.Images != nil && v.Images.MaxSize > 0 && resp.ContentLength > v.Images.MaxSize {
+		return fmt.Errorf("image size %d bytes exceeds %d bytes", resp.ContentLength, v.Images.MaxSize)
+	}
+	return nil
+}
+
+// toValidate parses a link and returns it with its unified form, if it should be validated.
+// Links to sample hosts, already validated links and links already reported in this run are skipped
+// and nil is returned.
+func (v *ValidatorWorker) toValidate(LinkDestination string, ContentSourcePath string) (*url.URL, string, error) {
 	LinkURL, err := url.Parse(strings.TrimSuffix(LinkDestination, "/"))
 	if err != nil {
-		return fmt.Errorf("error when parsing link in %s : %w", ContentSourcePath, err)
+		return nil, "", fmt.Errorf("error when parsing link in %s : %w", ContentSourcePath, err)
 	}
 	// ignore sample hosts e.g. localhost
 	host := LinkURL.Hostname()
 	if host == "localhost" || host == "127.0.0.1" {
-		return nil
+		return nil, "", nil
 	}
-	// unify links destination by excluding query, fragment & user info
+	// unify links destination by excluding user info and query & fragment unless configured
 	u := &url.URL{
 		Scheme: LinkURL.Scheme,
 		Host:   LinkURL.Host,
 		Path:   LinkURL.Path,
 	}
+	key := v.dedupKey(host)
+	if key.Query {
+		u.RawQuery = LinkURL.RawQuery
+	}
+	if key.Fragment {
+		u.Fragment = LinkURL.Fragment
+	}
 	unifiedURL := u.String()
-	if v.validated.exist(unifiedURL) {
-		return nil
+	if v.validated.exist(unifiedURL) || v.reported.exist(unifiedURL) {
+		return nil, "", nil
 	}
-	var client httpclient.Client
-	absLinkDestination := LinkURL.String()
-	repoHost, err := v.repository.Get(absLinkDestination)
-	if err != nil {
-		client = http.DefaultClient
-	} else {
+	return LinkURL, unifiedURL, nil
+}
+
+// dedupKey returns the DedupKey for host
+func (v *ValidatorWorker) dedupKey(host string) DedupKey {
+	for h, key := range v.HostDedupKeys {
+		if strings.EqualFold(h, host) {
+			return key
+		}
+	}
+	return v.DedupKey
+}
+
+// rejectsHEAD checks if host is one of the GETHosts
+func (v *ValidatorWorker) rejectsHEAD(host string) bool {
+	for _, h := range v.GETHosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// client returns the repository host client for a link or the default client
+// if there is no repository host for the link or it has no client
+func (v *ValidatorWorker) client(link string) httpclient.Client {
+	var client httpclient.Client = http.DefaultClient
+	if repoHost, err := v.repository.Get(link); err == nil && repoHost.GetClient() != nil {
 		client = repoHost.GetClient()
 	}
-	// try HEAD
-	if req, err = http.NewRequestWithContext(ctx, http.MethodHead, absLinkDestination, nil); err != nil {
-		return fmt.Errorf("failed to prepare HEAD validation request: %v", err)
+	return v.withRedirects(client)
+}
+
+// withRedirects returns a copy of an *http.Client client with the configured redirect policy,
+// the shared client is not modified
+func (v *ValidatorWorker) withRedirects(client httpclient.Client) httpclient.Client {
+	hc, ok := client.(*http.Client)
+	if v.Redirects == nil || !ok {
+		return client
 	}
-	if resp, err = doValidation(req, client); err != nil {
-		klog.Warningf("failed to validate absolute link for %s from source %s: %v\n",
-			LinkDestination, ContentSourcePath, err)
-	} else if resp.StatusCode >= 400 && resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusUnauthorized {
-		// on error status code different from authorization errors
-		// retry GET
-		if req, err = http.NewRequestWithContext(ctx, http.MethodGet, absLinkDestination, nil); err != nil {
-			return fmt.Errorf("failed to prepare GET validation request: %v", err)
+	c := *hc
+	c.CheckRedirect = v.Redirects.checkRedirect
+	return &c
+}
+
+// newRequest creates a validation request with the configured headers
+func (v *ValidatorWorker) newRequest(ctx context.Context, method string, link string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	v.setHeaders(req)
+	if v.ETags != nil {
+		v.ETags.setConditionalHeaders(req, link)
+	}
+	return req, nil
+}
+
+// setHeaders sets the configured headers and the credentials for the request host on a request
+func (v *ValidatorWorker) setHeaders(req *http.Request) {
+	for k, val := range v.Headers {
+		req.Header.Set(k, val)
+	}
+	for host, c := range v.Credentials {
+		if !strings.EqualFold(host, req.URL.Hostname()) {
+			continue
 		}
-		if resp, err = doValidation(req, client); err != nil {
-			klog.Warningf("failed to validate absolute link for %s from source %s: %v\n",
-				LinkDestination, ContentSourcePath, err)
-		} else if resp.StatusCode >= 400 && resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusUnauthorized {
-			klog.Warningf("failed to validate absolute link for %s from source %s: %v\n",
-				LinkDestination, ContentSourcePath, fmt.Errorf("HTTP Status %s", resp.Status))
+		if c.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		} else if c.Username != "" {
+			req.SetBasicAuth(c.Username, c.Password)
 		}
+		return
 	}
-	v.validated.add(unifiedURL)
-	return nil
 }
 
 // doValidation performs several attempts to execute http request if http status code is 429
-func doValidation(req *http.Request, client httpclient.Client) (*http.Response, error) {
-	intervals := []int{1, 5, 10, 20}
+func (v *ValidatorWorker) doValidation(req *http.Request, client httpclient.Client) (*http.Response, error) {
+	backoff := v.Backoff
+	if backoff == nil {
+		backoff = &DefaultBackoff
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		return resp, err
 	}
 	defer resp.Body.Close()
-	attempts := 0
-	for resp.StatusCode == http.StatusTooManyRequests && attempts < len(intervals)-1 {
+	for retry := 0; resp.StatusCode == http.StatusTooManyRequests && retry < len(backoff.Intervals); retry++ {
 		klog.Warningf("Retrying request!")
-		sleep := intervals[attempts] + rand.Intn(attempts+1)
-		// check for Retry-After Header and overwrite sleep time
+		delay := backoff.delay(retry, v.random())
+		// check for Retry-After Header and overwrite the delay
 		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
 			// support only value in seconds <= 5 min
-			var after int
-			if after, err = strconv.Atoi(retryAfter); err == nil && after <= 5*60 {
-				sleep = after
+			if after, err := strconv.Atoi(retryAfter); err == nil && after <= 5*60 {
+				delay = time.Duration(after) * time.Second
 			}
 		}
-		time.Sleep(time.Duration(sleep) * time.Second)
+		if err = v.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
 		resp, err = client.Do(req)
 		if err != nil {
 			return resp, err
 		}
-		attempts++
 	}
 	return resp, err
 }
 
-// linkSet holds link destinations that have been successfully validated
+// sleep waits for d or until ctx is done
+func (v *ValidatorWorker) sleep(ctx context.Context, d time.Duration) error {
+	if v.sleepFunc != nil {
+		return v.sleepFunc(ctx, d)
+	}
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// random returns a pseudo-random number in [0, 1)
+func (v *ValidatorWorker) random() float64 {
+	if v.randomFunc != nil {
+		return v.randomFunc()
+	}
+	return rand.Float64()
+}
+
+// LoadValidated loads the links validated in previous runs from a file written by SaveValidated.
+// Links validated more than ttl ago are not loaded and will be validated again.
+// A missing file is not an error.
+func (v *ValidatorWorker) LoadValidated(path string, ttl time.Duration) error {
+	cnt, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading validated links %s fails: %w", path, err)
+	}
+	links := map[string]time.Time{}
+	if err = json.Unmarshal(cnt, &links); err != nil {
+		return fmt.Errorf("parsing validated links %s fails: %w", path, err)
+	}
+	v.validated.mux.Lock()
+	defer v.validated.mux.Unlock()
+	for link, validatedAt := range links {
+		if time.Since(validatedAt) < ttl {
+			v.validated.set[link] = validatedAt
+		}
+	}
+	return nil
+}
+
+// SaveValidated writes the successfully validated links with the time of their validation as JSON object
+// to a file, broken links are not written and are validated again by the next run
+func (v *ValidatorWorker) SaveValidated(path string) error {
+	v.validated.mux.RLock()
+	defer v.validated.mux.RUnlock()
+	cnt, err := json.MarshalIndent(v.validated.set, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(path, cnt, 0644); err != nil {
+		return fmt.Errorf("writing validated links %s fails: %w", path, err)
+	}
+	return nil
+}
+
+// linkSet holds link destinations and the time they have been validated,
 // used to avoid redundant checks & HTTP Status 429
 type linkSet struct {
-	set map[string]struct{}
+	set map[string]time.Time
 	mux sync.RWMutex
 }
 
@@ -146,5 +673,5 @@ func (l *linkSet) exist(dest string) bool {
 func (l *linkSet) add(dest string) {
 	l.mux.Lock()
 	defer l.mux.Unlock()
-	l.set[dest] = struct{}{}
+	l.set[dest] = time.Now()
 }
@@ -6,18 +6,33 @@ package linkvalidator_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"embed"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/gardener/docforge/pkg/manifest"
 	"github.com/gardener/docforge/pkg/osfakes/httpclient/httpclientfakes"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts"
+	"github.com/gardener/docforge/pkg/readers/repositoryhosts/githubhttpcache"
 	"github.com/gardener/docforge/pkg/readers/repositoryhosts/repositoryhostsfakes"
 	"github.com/gardener/docforge/pkg/workers/linkvalidator"
+	"github.com/google/go-github/v43/github"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -38,6 +53,9 @@ var _ = Describe("Executing Validate", func() {
 		linkDestination   string
 		contentSourcePath string
 		ctx               context.Context
+		headers           map[string]string
+		getHosts          []string
+		hostDedupKeys     map[string]linkvalidator.DedupKey
 	)
 	BeforeEach(func() {
 		httpClient = &httpclientfakes.FakeClient{}
@@ -58,11 +76,19 @@ var _ = Describe("Executing Validate", func() {
 		}, nil)
 		linkDestination = "https://repoHost/fake_link"
 		contentSourcePath = "fake_path"
+		headers = nil
+		getHosts = nil
+		hostDedupKeys = nil
 	})
 	JustBeforeEach(func() {
 		worker, err = linkvalidator.NewValidatorWorker(repository)
 		Expect(worker).NotTo(BeNil())
 		Expect(err).NotTo(HaveOccurred())
+		if headers != nil {
+			worker.Headers = headers
+		}
+		worker.GETHosts = getHosts
+		worker.HostDedupKeys = hostDedupKeys
 
 		err = worker.Validate(ctx, linkDestination, contentSourcePath)
 	})
@@ -180,6 +206,96 @@ var _ = Describe("Executing Validate", func() {
 			Expect(handlerHttpClient.DoCallCount()).To(Equal(1))
 		})
 	})
+	Context("default headers", func() {
+		BeforeEach(func() {
+			httpClient.DoReturnsOnCall(0, &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(bytes.NewReader([]byte(""))),
+			}, nil)
+		})
+		It("sets them on HEAD and GET requests", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(httpClient.DoCallCount()).To(Equal(2))
+			for i := 0; i < 2; i++ {
+				req := httpClient.DoArgsForCall(i)
+				Expect(req.Header.Get("Accept")).To(Equal("*/*"))
+				Expect(req.Header.Get("User-Agent")).To(Equal("docforge"))
+			}
+		})
+		It("sets Accept-Encoding on HEAD requests only", func() {
+			Expect(httpClient.DoArgsForCall(0).Method).To(Equal(http.MethodHead))
+			Expect(httpClient.DoArgsForCall(0).Header.Get("Accept-Encoding")).To(Equal(linkvalidator.DefaultAcceptEncoding))
+			// the transport negotiates the encoding of the GET response bodies
+			Expect(httpClient.DoArgsForCall(1).Header.Get("Accept-Encoding")).To(BeEmpty())
+		})
+	})
+	Context("host rejects HEAD", func() {
+		BeforeEach(func() {
+			httpClient.DoCalls(func(req *http.Request) (*http.Response, error) {
+				status := http.StatusPartialContent
+				if req.Method == http.MethodHead {
+					status = http.StatusMethodNotAllowed
+				}
+				return &http.Response{
+					StatusCode: status,
+					Body:       io.NopCloser(bytes.NewReader([]byte(""))),
+				}, nil
+			})
+		})
+		It("falls back to GET", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(httpClient.DoCallCount()).To(Equal(2))
+			Expect(httpClient.DoArgsForCall(0).Method).To(Equal(http.MethodHead))
+			Expect(httpClient.DoArgsForCall(1).Method).To(Equal(http.MethodGet))
+		})
+		Context("host is configured to be validated with GET", func() {
+			BeforeEach(func() {
+				getHosts = []string{"RepoHost"}
+			})
+			It("skips HEAD", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(httpClient.DoCallCount()).To(Equal(1))
+				req := httpClient.DoArgsForCall(0)
+				Expect(req.Method).To(Equal(http.MethodGet))
+				Expect(req.Header.Get("Range")).To(Equal("bytes=0-0"))
+				Expect(req.Header.Get("Accept-Encoding")).To(Equal(linkvalidator.DefaultAcceptEncoding))
+			})
+		})
+	})
+	Context("links differing by query", func() {
+		BeforeEach(func() {
+			linkDestination = "https://repoHost/app?page=a#top"
+		})
+		It("validates them once", func() {
+			Expect(worker.Validate(ctx, "https://repoHost/app?page=b", contentSourcePath)).To(Succeed())
+			Expect(httpClient.DoCallCount()).To(Equal(1))
+		})
+		Context("host is query sensitive", func() {
+			BeforeEach(func() {
+				hostDedupKeys = map[string]linkvalidator.DedupKey{"RepoHost": {Query: true}}
+			})
+			It("validates them separately", func() {
+				Expect(worker.Validate(ctx, "https://repoHost/app?page=b", contentSourcePath)).To(Succeed())
+				Expect(httpClient.DoCallCount()).To(Equal(2))
+				Expect(httpClient.DoArgsForCall(1).URL.RawQuery).To(Equal("page=b"))
+			})
+			It("validates links differing by fragment once", func() {
+				Expect(worker.Validate(ctx, "https://repoHost/app?page=a#bottom", contentSourcePath)).To(Succeed())
+				Expect(httpClient.DoCallCount()).To(Equal(1))
+			})
+		})
+	})
+	Context("custom headers", func() {
+		BeforeEach(func() {
+			headers = map[string]string{"User-Agent": "custom-agent"}
+		})
+		It("sets the configured headers", func() {
+			Expect(err).NotTo(HaveOccurred())
+			req := httpClient.DoArgsForCall(0)
+			Expect(req.Header.Get("User-Agent")).To(Equal("custom-agent"))
+			Expect(req.Header.Get("Accept-Encoding")).To(Equal(linkvalidator.DefaultAcceptEncoding))
+		})
+	})
 	It("succeeded", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(httpClient.DoCallCount()).To(Equal(1))
@@ -191,3 +307,765 @@ var _ = Describe("Executing Validate", func() {
 		Expect(link).To(Equal("https://repoHost/fake_link"))
 	})
 })
+
+var _ = Describe("Validating with ETag cache", func() {
+	var (
+		httpClient *httpclientfakes.FakeClient
+		repository *repositoryhostsfakes.FakeRegistry
+		dir        string
+		cacheFile  string
+	)
+	BeforeEach(func() {
+		httpClient = &httpclientfakes.FakeClient{}
+		repository = &repositoryhostsfakes.FakeRegistry{}
+		repoHost := &repositoryhostsfakes.FakeRepositoryHost{}
+		repoHost.GetClientReturns(httpClient)
+		repository.GetReturns(repoHost, nil)
+		var err error
+		dir, err = os.MkdirTemp("", "validator")
+		Expect(err).NotTo(HaveOccurred())
+		cacheFile = filepath.Join(dir, "etags.json")
+	})
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+	validate := func() {
+		cache, err := linkvalidator.LoadETagCache(cacheFile)
+		Expect(err).NotTo(HaveOccurred())
+		worker, err := linkvalidator.NewValidatorWorker(repository)
+		Expect(err).NotTo(HaveOccurred())
+		worker.ETags = cache
+		Expect(worker.Validate(context.Background(), "https://repoHost/fake_link", "fake_path")).To(Succeed())
+		Expect(cache.Save()).To(Succeed())
+	}
+	It("sends If-None-Match and accepts 304 on the next run", func() {
+		httpClient.DoReturnsOnCall(0, &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Etag": []string{`"abc"`}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(""))),
+		}, nil)
+		httpClient.DoReturnsOnCall(1, &http.Response{
+			StatusCode: http.StatusNotModified,
+			Body:       io.NopCloser(bytes.NewReader([]byte(""))),
+		}, nil)
+		validate()
+		Expect(httpClient.DoArgsForCall(0).Header.Get("If-None-Match")).To(BeEmpty())
+		validate()
+		Expect(httpClient.DoCallCount()).To(Equal(2))
+		Expect(httpClient.DoArgsForCall(1).Header.Get("If-None-Match")).To(Equal(`"abc"`))
+	})
+})
+
+var _ = Describe("Validating with credentials", func() {
+	var (
+		httpClient *httpclientfakes.FakeClient
+		worker     *linkvalidator.ValidatorWorker
+	)
+	BeforeEach(func() {
+		httpClient = &httpclientfakes.FakeClient{}
+		httpClient.DoReturns(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(""))),
+		}, nil)
+		repository := &repositoryhostsfakes.FakeRegistry{}
+		repoHost := &repositoryhostsfakes.FakeRepositoryHost{}
+		repoHost.GetClientReturns(httpClient)
+		repository.GetReturns(repoHost, nil)
+		var err error
+		worker, err = linkvalidator.NewValidatorWorker(repository)
+		Expect(err).NotTo(HaveOccurred())
+		worker.Credentials = map[string]linkvalidator.Credentials{
+			"Bearer.Host": {Token: "secret"},
+			"basic.host":  {Username: "user", Password: "pass"},
+		}
+	})
+	It("authenticates the requests to matching hosts only", func() {
+		Expect(worker.Validate(context.Background(), "https://bearer.host/page", "fake_path")).To(Succeed())
+		Expect(worker.Validate(context.Background(), "https://basic.host/page", "fake_path")).To(Succeed())
+		Expect(worker.Validate(context.Background(), "https://anonymous.host/page", "fake_path")).To(Succeed())
+		Expect(httpClient.DoCallCount()).To(Equal(3))
+		Expect(httpClient.DoArgsForCall(0).Header.Get("Authorization")).To(Equal("Bearer secret"))
+		user, pass, ok := httpClient.DoArgsForCall(1).BasicAuth()
+		Expect(ok).To(BeTrue())
+		Expect(user + ":" + pass).To(Equal("user:pass"))
+		Expect(httpClient.DoArgsForCall(2).Header.Get("Authorization")).To(BeEmpty())
+	})
+	It("redacts them when formatted", func() {
+		c := worker.Credentials["basic.host"]
+		Expect(fmt.Sprintf("%v %+v %#v", c, c, c)).NotTo(ContainSubstring("pass"))
+	})
+})
+
+var _ = Describe("Persisting validated links", func() {
+	var (
+		httpClient *httpclientfakes.FakeClient
+		repository *repositoryhostsfakes.FakeRegistry
+		dir        string
+		file       string
+	)
+	BeforeEach(func() {
+		httpClient = &httpclientfakes.FakeClient{}
+		httpClient.DoReturns(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(""))),
+		}, nil)
+		repository = &repositoryhostsfakes.FakeRegistry{}
+		repoHost := &repositoryhostsfakes.FakeRepositoryHost{}
+		repoHost.GetClientReturns(httpClient)
+		repository.GetReturns(repoHost, nil)
+		var err error
+		dir, err = os.MkdirTemp("", "validator")
+		Expect(err).NotTo(HaveOccurred())
+		file = filepath.Join(dir, "validated.json")
+	})
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+	validate := func(ttl time.Duration) {
+		worker, err := linkvalidator.NewValidatorWorker(repository)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(worker.LoadValidated(file, ttl)).To(Succeed())
+		Expect(worker.Validate(context.Background(), "https://repoHost/fake_link?a=b", "fake_path")).To(Succeed())
+		Expect(worker.SaveValidated(file)).To(Succeed())
+	}
+	It("round-trips the validated links", func() {
+		validate(time.Hour)
+		Expect(httpClient.DoCallCount()).To(Equal(1))
+		cnt, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		links := map[string]time.Time{}
+		Expect(json.Unmarshal(cnt, &links)).To(Succeed())
+		Expect(links).To(HaveKey("https://repoHost/fake_link"))
+		validate(time.Hour)
+		Expect(httpClient.DoCallCount()).To(Equal(1))
+	})
+	It("doesn't persist broken links", func() {
+		httpClient.DoReturns(&http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(bytes.NewReader([]byte(""))),
+		}, nil)
+		validate(time.Hour)
+		Expect(httpClient.DoCallCount()).To(Equal(2))
+		cnt, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(cnt)).NotTo(ContainSubstring("fake_link"))
+		validate(time.Hour)
+		Expect(httpClient.DoCallCount()).To(Equal(4))
+	})
+	It("reports broken links once per run", func() {
+		httpClient.DoReturns(&http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(bytes.NewReader([]byte(""))),
+		}, nil)
+		worker, err := linkvalidator.NewValidatorWorker(repository)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(worker.Validate(context.Background(), "https://repoHost/fake_link", "one.md")).To(Succeed())
+		Expect(worker.Validate(context.Background(), "https://repoHost/fake_link", "two.md")).To(Succeed())
+		Expect(httpClient.DoCallCount()).To(Equal(2))
+	})
+	It("validates links again when TTL is expired", func() {
+		cnt := fmt.Sprintf(`{"https://repoHost/fake_link": %q}`, time.Now().Add(-2*time.Hour).Format(time.RFC3339))
+		Expect(os.WriteFile(file, []byte(cnt), 0644)).To(Succeed())
+		validate(time.Hour)
+		Expect(httpClient.DoCallCount()).To(Equal(1))
+	})
+})
+
+var _ = Describe("Checking links with health checks", func() {
+	var (
+		server   *httptest.Server
+		worker   *linkvalidator.ValidatorWorker
+		down     bool
+		requests map[string]int
+	)
+	BeforeEach(func() {
+		down = false
+		requests = map[string]int{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests[r.URL.Path]++
+			switch {
+			case down:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			case r.URL.Path == "/missing":
+				w.WriteHeader(http.StatusNotFound)
+			case r.URL.Path == "/flaky" && requests["/flaky"] <= 2:
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		repository := &repositoryhostsfakes.FakeRegistry{}
+		repository.GetReturns(nil, errors.New("no sutiable repository host"))
+		var err error
+		worker, err = linkvalidator.NewValidatorWorker(repository)
+		Expect(err).NotTo(HaveOccurred())
+		worker.HealthChecks = map[string]linkvalidator.HealthCheck{"127.0.0.1": {Path: "/healthz", Delay: time.Millisecond}}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("classifies a failing link to a healthy host as broken", func() {
+		err := worker.CheckLink(context.Background(), server.URL+"/missing")
+		Expect(err).To(MatchError("HTTP Status 404 Not Found"))
+		Expect(errors.Is(err, linkvalidator.ErrHostDown)).To(BeFalse())
+		Expect(requests["/healthz"]).To(Equal(1))
+		Expect(requests["/missing"]).To(Equal(4))
+	})
+	It("retries a failing link after the health check", func() {
+		Expect(worker.CheckLink(context.Background(), server.URL+"/flaky")).To(Succeed())
+		Expect(requests["/healthz"]).To(Equal(1))
+		Expect(requests["/flaky"]).To(Equal(3))
+	})
+	It("classifies a failing link to an unhealthy host as host down", func() {
+		down = true
+		err := worker.CheckLink(context.Background(), server.URL+"/missing")
+		Expect(errors.Is(err, linkvalidator.ErrHostDown)).To(BeTrue())
+		Expect(requests["/missing"]).To(Equal(2))
+	})
+	It("classifies failing links without health check as broken", func() {
+		worker.HealthChecks = nil
+		down = true
+		err := worker.CheckLink(context.Background(), server.URL+"/missing")
+		Expect(err).To(MatchError("HTTP Status 503 Service Unavailable"))
+		Expect(requests).NotTo(HaveKey("/healthz"))
+	})
+})
+
+var _ = Describe("Checking links with redirect policy", func() {
+	var (
+		server   *httptest.Server
+		worker   *linkvalidator.ValidatorWorker
+		requests int32
+	)
+	BeforeEach(func() {
+		requests = 0
+		// /redirect/N redirects N times before responding
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			var n int
+			if _, err := fmt.Sscanf(r.URL.Path, "/redirect/%d", &n); err == nil && n > 0 {
+				http.Redirect(w, r, fmt.Sprintf("/redirect/%d", n-1), http.StatusFound)
+			}
+		}))
+		repository := &repositoryhostsfakes.FakeRegistry{}
+		repository.GetReturns(nil, errors.New("no sutiable repository host"))
+		var err error
+		worker, err = linkvalidator.NewValidatorWorker(repository)
+		Expect(err).NotTo(HaveOccurred())
+		worker.Redirects = &linkvalidator.RedirectPolicy{Max: 3}
+	})
+	AfterEach(func() {
+		server.Close()
+		Expect(http.DefaultClient.CheckRedirect).To(BeNil())
+	})
+	It("follows redirects within the cap", func() {
+		Expect(worker.CheckLink(context.Background(), server.URL+"/redirect/3")).To(Succeed())
+	})
+	It("fails on redirects beyond the cap", func() {
+		err := worker.CheckLink(context.Background(), server.URL+"/redirect/4")
+		Expect(err).To(MatchError(ContainSubstring("stopped after 3 redirects")))
+	})
+	It("uses the client default without policy", func() {
+		worker.Redirects = nil
+		Expect(worker.CheckLink(context.Background(), server.URL+"/redirect/4")).To(Succeed())
+	})
+	It("accepts the redirect response if redirects are disabled", func() {
+		worker.Redirects.Disabled = true
+		Expect(worker.CheckLink(context.Background(), server.URL+"/redirect/4")).To(Succeed())
+		Expect(atomic.LoadInt32(&requests)).To(Equal(int32(1)))
+	})
+})
+
+var _ = Describe("Checking links in bulk", func() {
+	var (
+		server   *httptest.Server
+		worker   *linkvalidator.ValidatorWorker
+		ctx      context.Context
+		cancel   context.CancelFunc
+		requests int32
+		links    []string
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			switch r.URL.Path {
+			case "/cancel":
+				// cancel the run and block until the request is aborted
+				cancel()
+				<-r.Context().Done()
+			case "/broken":
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		links = []string{server.URL + "/one", server.URL + "/broken", server.URL + "/cancel"}
+		for i := 0; i < 8; i++ {
+			links = append(links, fmt.Sprintf("%s/link%d", server.URL, i))
+		}
+		repository := &repositoryhostsfakes.FakeRegistry{}
+		repository.GetReturns(nil, errors.New("no sutiable repository host"))
+		var err error
+		worker, err = linkvalidator.NewValidatorWorker(repository)
+		Expect(err).NotTo(HaveOccurred())
+	})
+	AfterEach(func() {
+		cancel()
+		server.Close()
+	})
+	It("returns the results collected before the cancellation", func() {
+		goroutines := runtime.NumGoroutine()
+		results := worker.CheckLinks(ctx, links, 1)
+		Expect(results).To(HaveLen(2))
+		Expect(results[0]).To(Equal(linkvalidator.ValidationResult{Link: server.URL + "/one"}))
+		Expect(results[1].Link).To(Equal(server.URL + "/broken"))
+		Expect(results[1].Err).To(MatchError("HTTP Status 404 Not Found"))
+		Expect(atomic.LoadInt32(&requests)).To(Equal(int32(4)))
+		http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+		Eventually(runtime.NumGoroutine).Should(BeNumerically("<=", goroutines))
+	})
+	It("checks all links without cancellation", func() {
+		results := worker.CheckLinks(context.Background(), links[:2], 4)
+		Expect(results).To(HaveLen(2))
+		checked := map[string]error{}
+		for _, result := range results {
+			checked[result.Link] = result.Err
+		}
+		Expect(checked[server.URL+"/one"]).To(BeNil())
+		Expect(checked[server.URL+"/broken"]).To(MatchError("HTTP Status 404 Not Found"))
+	})
+})
+
+var _ = Describe("Checking images", func() {
+	var (
+		server *httptest.Server
+		worker *linkvalidator.ValidatorWorker
+	)
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/page.html":
+				w.Header().Set("Content-Type", "text/html")
+				_, _ = w.Write([]byte("<html></html>"))
+			case "/missing.png":
+				w.WriteHeader(http.StatusNotFound)
+			case "/private.png":
+				if r.Header.Get("Authorization") != "Bearer secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write([]byte("png"))
+			default:
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write(bytes.Repeat([]byte{0}, 2048))
+			}
+		}))
+		repository := &repositoryhostsfakes.FakeRegistry{}
+		repository.GetReturns(nil, errors.New("no sutiable repository host"))
+		var err error
+		worker, err = linkvalidator.NewValidatorWorker(repository)
+		Expect(err).NotTo(HaveOccurred())
+		worker.Images = &linkvalidator.ImageValidation{MaxSize: 1024}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("fails on non-image content type", func() {
+		err := worker.CheckImage(context.Background(), server.URL+"/page.html")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`unexpected Content-Type "text/html"`))
+	})
+	It("fails on missing image", func() {
+		err := worker.CheckImage(context.Background(), server.URL+"/missing.png")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("404"))
+	})
+	It("fails on oversized image", func() {
+		err := worker.CheckImage(context.Background(), server.URL+"/logo.png")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("image size 2048 bytes exceeds 1024 bytes"))
+	})
+	It("succeeds on image within the size limit", func() {
+		worker.Images.MaxSize = 4096
+		Expect(worker.CheckImage(context.Background(), server.URL+"/logo.png")).To(Succeed())
+	})
+	Context("image requires authentication", func() {
+		It("succeeds only with credentials", func() {
+			Expect(worker.CheckImage(context.Background(), server.URL+"/private.png")).NotTo(Succeed())
+			worker.Credentials = map[string]linkvalidator.Credentials{"127.0.0.1": {Token: "secret"}}
+			Expect(worker.CheckImage(context.Background(), server.URL+"/private.png")).To(Succeed())
+		})
+	})
+	Context("repository host has no client", func() {
+		BeforeEach(func() {
+			repository := &repositoryhostsfakes.FakeRegistry{}
+			repository.GetReturns(&repositoryhostsfakes.FakeRepositoryHost{}, nil)
+			var err error
+			worker, err = linkvalidator.NewValidatorWorker(repository)
+			Expect(err).NotTo(HaveOccurred())
+		})
+		It("uses the default client for local and remote links", func() {
+			Expect(worker.Validate(context.Background(), "./local.md", "fake_path")).To(Succeed())
+			Expect(worker.CheckImage(context.Background(), server.URL+"/logo.png")).To(Succeed())
+		})
+	})
+})
+
+//go:embed tests/*
+var manifests embed.FS
+
+var _ = Describe("Listing external links", func() {
+	It("lists only the external links with their nodes", func() {
+		registry := repositoryhostsfakes.FilesystemRegistry(manifests)
+		nodes, err := manifest.ResolveManifest("tests/inventory.yaml", registry)
+		Expect(err).NotTo(HaveOccurred())
+		links, err := linkvalidator.ExternalLinks(context.Background(), nodes, registry)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(links).To(Equal([]linkvalidator.ExternalLink{
+			{URL: "http://example.com/page", Nodes: []string{"sub/two.md"}},
+			{URL: "https://gardener.cloud/docs", Nodes: []string{"one.md", "sub/two.md"}},
+			{URL: "https://github.com/gardener/docforge", Nodes: []string{"one.md", "sub/two.md"}},
+		}))
+	})
+	It("lists only the links of the selected lines and sections", func() {
+		registry := repositoryhostsfakes.FilesystemRegistry(manifests)
+		node := &manifest.Node{Type: "file", FileType: manifest.FileType{File: "part.md", MultiSource: []string{"tests/two.md#L1-L3", "tests/one.md#one"}}}
+		links, err := linkvalidator.ExternalLinks(context.Background(), []*manifest.Node{node}, registry)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(links).To(Equal([]linkvalidator.ExternalLink{
+			{URL: "https://gardener.cloud/docs", Nodes: []string{"part.md"}},
+			{URL: "https://github.com/gardener/docforge", Nodes: []string{"part.md"}},
+		}))
+	})
+})
+
+var _ = Describe("Listing image assets", func() {
+	It("lists the local and external images of a document", func() {
+		host := &repositoryhostsfakes.FakeRepositoryHost{}
+		host.ReadCalls(func(_ context.Context, source string) ([]byte, error) {
+			return manifests.ReadFile("tests/" + path.Base(source))
+		})
+		host.ToAbsLinkCalls(func(source string, link string) (string, error) {
+			base, err := url.Parse(source)
+			if err != nil {
+				return "", err
+			}
+			rel, err := url.Parse(link)
+			if err != nil {
+				return "", err
+			}
+			return base.ResolveReference(rel).String(), nil
+		})
+		registry := &repositoryhostsfakes.FakeRegistry{}
+		registry.GetReturns(host, nil)
+		node := &manifest.Node{Type: "file", FileType: manifest.FileType{File: "images.md", Source: "https://github.com/gardener/docforge/blob/master/docs/images.md#images"}}
+		assets, err := linkvalidator.ImageAssets(context.Background(), node, registry)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(assets).To(Equal([]linkvalidator.ImageAsset{
+			{URL: "https://github.com/gardener/docforge/blob/master/img/logo.png"},
+			{URL: "https://github.com/gardener/docforge/blob/master/docs/diagram.svg"},
+			{URL: "https://badge.io/build.svg", External: true},
+		}))
+	})
+})
+
+var _ = Describe("Checking links with move hints", func() {
+	var (
+		server *httptest.Server
+		worker *linkvalidator.ValidatorWorker
+	)
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/gardener/docforge/blob/master/docs/new.md":
+			case "/api/v3/repos/gardener/docforge/commits":
+				if r.URL.Query().Get("path") == "docs/old.md" && r.URL.Query().Get("sha") == "master" {
+					_, _ = w.Write([]byte(`[{"sha": "abc"}]`))
+					return
+				}
+				_, _ = w.Write([]byte(`[]`))
+			case "/api/v3/repos/gardener/docforge/commits/abc":
+				_, _ = w.Write([]byte(`{"sha": "abc", "files": [{"filename": "docs/new.md", "previous_filename": "docs/old.md", "status": "renamed"}]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		serverURL, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		client, err := github.NewEnterpriseClient(server.URL, server.URL, server.Client())
+		Expect(err).NotTo(HaveOccurred())
+		ghc := githubhttpcache.NewGHC("github", client, client.Repositories, client.Git, server.Client(), nil, []string{serverURL.Host}, nil, manifest.ParsingOptions{}, "")
+		worker, err = linkvalidator.NewValidatorWorker(repositoryhosts.NewRegistry(ghc))
+		Expect(err).NotTo(HaveOccurred())
+		worker.MoveHints = true
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("hints where a renamed file was moved", func() {
+		link := server.URL + "/gardener/docforge/blob/master/docs/old.md"
+		results := worker.CheckLinks(context.Background(), []string{link}, 1)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Err).To(MatchError(ContainSubstring("404")))
+		Expect(results[0].Hint).To(Equal("moved to " + server.URL + "/gardener/docforge/blob/master/docs/new.md"))
+	})
+	It("has no hint for links without known moves", func() {
+		link := server.URL + "/gardener/docforge/blob/master/docs/missing.md"
+		results := worker.CheckLinks(context.Background(), []string{link}, 1)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Err).To(HaveOccurred())
+		Expect(results[0].Hint).To(BeEmpty())
+	})
+	It("has no hint if move hints are disabled", func() {
+		worker.MoveHints = false
+		link := server.URL + "/gardener/docforge/blob/master/docs/old.md"
+		results := worker.CheckLinks(context.Background(), []string{link}, 1)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Hint).To(BeEmpty())
+	})
+	It("has no hint for valid links", func() {
+		link := server.URL + "/gardener/docforge/blob/master/docs/new.md"
+		Expect(worker.CheckLinks(context.Background(), []string{link}, 1)).To(Equal([]linkvalidator.ValidationResult{{Link: link}}))
+	})
+})
+
+var _ = Describe("Checking anchors of markdown links", func() {
+	var (
+		server  *httptest.Server
+		worker  *linkvalidator.ValidatorWorker
+		fetches int32
+		zipped  http.Header
+	)
+	BeforeEach(func() {
+		fetches = 0
+		zipped = nil
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/gardener/docforge/blob/master/docs/guide.md", "/gardener/docforge/blob/master/docs/zipped.md",
+				"/gardener/docforge/blob/master/docs/unchanged.md":
+			case "/gardener/docforge/raw/master/docs/guide.md":
+				atomic.AddInt32(&fetches, 1)
+				_, _ = w.Write([]byte("# Guide\n\n## Install\n\nRun it.\n\n## Install\n\nAgain.\n"))
+			case "/gardener/docforge/raw/master/docs/zipped.md":
+				zipped = r.Header.Clone()
+				if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+					_, _ = w.Write([]byte("# Zipped\n\n## Install\n"))
+					return
+				}
+				w.Header().Set("Content-Encoding", "gzip")
+				gz := gzip.NewWriter(w)
+				_, _ = gz.Write([]byte("# Zipped\n\n## Install\n"))
+				_ = gz.Close()
+			case "/gardener/docforge/raw/master/docs/unchanged.md":
+				w.WriteHeader(http.StatusNotModified)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		serverURL, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		client, err := github.NewEnterpriseClient(server.URL, server.URL, server.Client())
+		Expect(err).NotTo(HaveOccurred())
+		ghc := githubhttpcache.NewGHC("github", client, client.Repositories, client.Git, server.Client(), nil, []string{serverURL.Host}, nil, manifest.ParsingOptions{}, "")
+		worker, err = linkvalidator.NewValidatorWorker(repositoryhosts.NewRegistry(ghc))
+		Expect(err).NotTo(HaveOccurred())
+		worker.Anchors = true
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("accepts links to heading anchors", func() {
+		Expect(worker.CheckLink(context.Background(), server.URL+"/gardener/docforge/blob/master/docs/guide.md#install")).To(Succeed())
+		Expect(worker.CheckLink(context.Background(), server.URL+"/gardener/docforge/blob/master/docs/guide.md#install-1")).To(Succeed())
+		Expect(worker.CheckLink(context.Background(), server.URL+"/gardener/docforge/blob/master/docs/guide.md#user-content-guide")).To(Succeed())
+		Expect(fetches).To(Equal(int32(1)))
+	})
+	It("fails for links to missing anchors", func() {
+		err := worker.CheckLink(context.Background(), server.URL+"/gardener/docforge/blob/master/docs/guide.md#usage")
+		Expect(errors.Is(err, linkvalidator.ErrAnchorNotFound)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("#usage")))
+	})
+	It("doesn't check line fragments", func() {
+		Expect(worker.CheckLink(context.Background(), server.URL+"/gardener/docforge/blob/master/docs/guide.md#L3")).To(Succeed())
+		Expect(fetches).To(BeZero())
+	})
+	It("doesn't check anchors if disabled", func() {
+		worker.Anchors = false
+		Expect(worker.CheckLink(context.Background(), server.URL+"/gardener/docforge/blob/master/docs/guide.md#usage")).To(Succeed())
+		Expect(fetches).To(BeZero())
+	})
+	It("fetches gzip-encoded content with a plain GET", func() {
+		dir, err := os.MkdirTemp("", "anchors")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		cacheFile := filepath.Join(dir, "etags.json")
+		cnt := fmt.Sprintf(`{%q: {"etag": "\"abc\"", "lastModified": "Mon, 02 Jan 2006 15:04:05 GMT"}}`, server.URL+"/gardener/docforge/raw/master/docs/zipped.md")
+		Expect(os.WriteFile(cacheFile, []byte(cnt), 0644)).To(Succeed())
+		worker.ETags, err = linkvalidator.LoadETagCache(cacheFile)
+		Expect(err).NotTo(HaveOccurred())
+		worker.Headers = map[string]string{"Accept-Encoding": "gzip"}
+		Expect(worker.CheckLink(context.Background(), server.URL+"/gardener/docforge/blob/master/docs/zipped.md#install")).To(Succeed())
+		Expect(zipped.Get("If-None-Match")).To(BeEmpty())
+		Expect(zipped.Get("If-Modified-Since")).To(BeEmpty())
+		err = worker.CheckLink(context.Background(), server.URL+"/gardener/docforge/blob/master/docs/zipped.md#usage")
+		Expect(errors.Is(err, linkvalidator.ErrAnchorNotFound)).To(BeTrue())
+	})
+	It("accepts anchors that can't be verified", func() {
+		Expect(worker.CheckLink(context.Background(), server.URL+"/gardener/docforge/blob/master/docs/unchanged.md#usage")).To(Succeed())
+	})
+})
+
+var _ = Describe("Retrying rate limited links", func() {
+	var (
+		server      *httptest.Server
+		worker      *linkvalidator.ValidatorWorker
+		limited     int32
+		retryAfter  string
+		delays      []time.Duration
+		randomValue float64
+	)
+	BeforeEach(func() {
+		limited = 10
+		retryAfter = ""
+		delays = nil
+		randomValue = 0.5
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&limited, -1) >= 0 {
+				if retryAfter != "" {
+					w.Header().Set("Retry-After", retryAfter)
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		}))
+		repository := &repositoryhostsfakes.FakeRegistry{}
+		repository.GetReturns(nil, errors.New("no sutiable repository host"))
+		var err error
+		worker, err = linkvalidator.NewValidatorWorker(repository)
+		Expect(err).NotTo(HaveOccurred())
+		worker.SetClock(func(_ context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		}, func() float64 { return randomValue })
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("uses the default backoff with full jitter", func() {
+		limited = 2
+		Expect(worker.CheckLink(context.Background(), server.URL)).To(Succeed())
+		Expect(delays).To(Equal([]time.Duration{500 * time.Millisecond, 2500 * time.Millisecond}))
+	})
+	It("fails after the configured retries", func() {
+		worker.Backoff = &linkvalidator.Backoff{Intervals: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}}
+		// HEAD and GET are retried
+		Expect(worker.CheckLink(context.Background(), server.URL)).To(MatchError(ContainSubstring("429")))
+		Expect(delays).To(Equal([]time.Duration{
+			time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+			time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+		}))
+	})
+	It("keeps jittered delays within the bounds", func() {
+		worker.SetClock(func(_ context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		}, nil)
+		worker.Backoff = &linkvalidator.Backoff{Intervals: []time.Duration{time.Second, 10 * time.Second}, Jitter: 0.5}
+		for i := 0; i < 20; i++ {
+			limited = 2
+			Expect(worker.CheckLink(context.Background(), server.URL)).To(Succeed())
+		}
+		Expect(delays).To(HaveLen(40))
+		for i, d := range delays {
+			interval := []time.Duration{time.Second, 10 * time.Second}[i%2]
+			Expect(d).To(BeNumerically(">=", interval/2))
+			Expect(d).To(BeNumerically("<=", interval))
+		}
+	})
+	It("uses the Retry-After header", func() {
+		limited = 1
+		retryAfter = "7"
+		Expect(worker.CheckLink(context.Background(), server.URL)).To(Succeed())
+		Expect(delays).To(Equal([]time.Duration{7 * time.Second}))
+	})
+})
+
+var _ = Describe("Checking links with robots.txt", func() {
+	var (
+		server   *httptest.Server
+		worker   *linkvalidator.ValidatorWorker
+		robots   string
+		failures int
+		requests map[string]int
+		mux      sync.Mutex
+	)
+	BeforeEach(func() {
+		failures = 0
+		robots = "# robots\nUser-agent: *\nDisallow: /private\n\nUser-agent: docforge\nUser-agent: other\nDisallow: /internal\nAllow: /internal/public\nDisallow: /*.pdf$\n"
+		requests = map[string]int{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mux.Lock()
+			requests[r.URL.Path]++
+			mux.Unlock()
+			if r.URL.Path == "/robots.txt" {
+				if failures > 0 {
+					failures--
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				if robots == "" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(robots))
+			}
+		}))
+		repository := &repositoryhostsfakes.FakeRegistry{}
+		repository.GetReturns(nil, errors.New("no sutiable repository host"))
+		var err error
+		worker, err = linkvalidator.NewValidatorWorker(repository)
+		Expect(err).NotTo(HaveOccurred())
+		worker.Robots = linkvalidator.NewRobotsCache("docforge/1.0")
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+	It("skips the disallowed paths for the user agent", func() {
+		err := worker.CheckLink(context.Background(), server.URL+"/internal/page")
+		Expect(errors.Is(err, linkvalidator.ErrRobotsDisallowed)).To(BeTrue())
+		Expect(worker.CheckLink(context.Background(), server.URL+"/internal/public/page")).To(Succeed())
+		Expect(worker.CheckLink(context.Background(), server.URL+"/private/page")).To(Succeed())
+		Expect(errors.Is(worker.CheckLink(context.Background(), server.URL+"/docs/guide.pdf"), linkvalidator.ErrRobotsDisallowed)).To(BeTrue())
+		Expect(worker.CheckLink(context.Background(), server.URL+"/docs/guide.pdf?download")).To(Succeed())
+		Expect(requests).To(Equal(map[string]int{
+			"/robots.txt":           1,
+			"/internal/public/page": 1,
+			"/private/page":         1,
+			"/docs/guide.pdf":       1,
+		}))
+	})
+	It("applies the default group to other user agents", func() {
+		worker.Robots = linkvalidator.NewRobotsCache("crawler")
+		Expect(errors.Is(worker.CheckLink(context.Background(), server.URL+"/private/page"), linkvalidator.ErrRobotsDisallowed)).To(BeTrue())
+		Expect(worker.CheckLink(context.Background(), server.URL+"/internal/page")).To(Succeed())
+	})
+	It("allows all links without robots.txt", func() {
+		robots = ""
+		Expect(worker.CheckLink(context.Background(), server.URL+"/internal/page")).To(Succeed())
+		Expect(worker.CheckLink(context.Background(), server.URL+"/private/page")).To(Succeed())
+		Expect(requests["/robots.txt"]).To(Equal(1))
+	})
+	It("fetches robots.txt again after a failed fetch", func() {
+		failures = 1
+		Expect(worker.CheckLink(context.Background(), server.URL+"/internal/page")).To(Succeed())
+		err := worker.CheckLink(context.Background(), server.URL+"/internal/page")
+		Expect(errors.Is(err, linkvalidator.ErrRobotsDisallowed)).To(BeTrue())
+		Expect(requests["/robots.txt"]).To(Equal(2))
+	})
+	It("fetches robots.txt independently of the canceled link checks", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_ = worker.CheckLink(ctx, server.URL+"/docs/page")
+		err := worker.CheckLink(context.Background(), server.URL+"/internal/page")
+		Expect(errors.Is(err, linkvalidator.ErrRobotsDisallowed)).To(BeTrue())
+		Expect(requests["/robots.txt"]).To(Equal(1))
+	})
+})
@@ -28,6 +28,8 @@ type Interface interface {
 	// returns true if the task is added and false if it is skipped
 	// (e.g. if the TaskQueue is stopped or failFast situation)
 	AddTask(task interface{}) bool
+	// SetOnProgress sets a callback invoked after each task is processed, it must be set before the queue is started
+	SetOnProgress(onProgress ProgressFunc)
 }
 
 // QueueController can Start/Stop the queue and see its status
@@ -73,11 +75,20 @@ type taskQueue struct {
 	stopped bool
 	// processed tasks count
 	tc uint32
+	// onProgress is invoked after each task is processed
+	onProgress ProgressFunc
+	// serializes onProgress calls
+	progressMux sync.Mutex
 }
 
 // The WorkerFunc type declares workers functional interface
 type WorkerFunc func(ctx context.Context, task interface{}) error
 
+// The ProgressFunc type declares the progress callback functional interface.
+// It's called with the processed task and the error returned by the WorkerFunc.
+// Calls are serialized, so the callback doesn't need to be safe for concurrent use.
+type ProgressFunc func(task interface{}, err error)
+
 // New create an empty task queue
 func New(id string, size int, workFunc WorkerFunc, failFast bool, wg *sync.WaitGroup) (Interface, error) {
 	if size < minWorkerSize || size > maxWorkerSize {
@@ -144,6 +155,11 @@ func (jq *taskQueue) AddTask(task interface{}) bool {
 	return false
 }
 
+// SetOnProgress sets a callback invoked after each task is processed
+func (jq *taskQueue) SetOnProgress(onProgress ProgressFunc) {
+	jq.onProgress = onProgress
+}
+
 // GetErrorList returns the errors, occurred during task processing
 func (jq *taskQueue) GetErrorList() *multierror.Error {
 	return jq.errList
@@ -186,23 +202,34 @@ func (jq *taskQueue) work(ctx context.Context) {
 	}
 }
 
-// runWorkFunc runs the work func, if error occurs appends the error to the errList
-// and finally decrease wg counter
+// runWorkFunc runs the work func, if error occurs appends the error to the errList,
+// reports the progress and finally decrease wg counter
 func (jq *taskQueue) runWorkFunc(ctx context.Context, t interface{}) {
 	defer jq.wg.Done()
 	defer atomic.AddUint32(&jq.tc, 1)
+	if !jq.shouldProcess() {
+		return
+	}
+	err := jq.recoverWorkFunc(ctx, t)
+	if err != nil {
+		jq.appendError(err)
+	}
+	if jq.onProgress != nil {
+		jq.progressMux.Lock()
+		defer jq.progressMux.Unlock()
+		jq.onProgress(t, err)
+	}
+}
+
+// recoverWorkFunc runs the work func and converts a panic into an error
+func (jq *taskQueue) recoverWorkFunc(ctx context.Context, t interface{}) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			err := fmt.Errorf("panic in %s for task %v recovered: %v", jq.id, t, r)
+			err = fmt.Errorf("panic in %s for task %v recovered: %v", jq.id, t, r)
 			klog.Warning(err.Error(), "\n", string(debug.Stack()))
-			jq.appendError(err)
 		}
 	}()
-	if jq.shouldProcess() {
-		if err := jq.workFunc(ctx, t); err != nil {
-			jq.appendError(err)
-		}
-	}
+	return jq.workFunc(ctx, t)
 }
 
 // appendError appends an error in the errList
@@ -149,6 +149,35 @@ var _ = Describe("Jobs", func() {
 			Expect(queue.AddTask(&task{})).To(BeFalse())
 		})
 	})
+	When("progress callback is set", func() {
+		var (
+			progress map[interface{}]error
+			calls    int
+		)
+		BeforeEach(func() {
+			progress = map[interface{}]error{}
+			calls = 0
+		})
+		JustBeforeEach(func() {
+			queue.SetOnProgress(func(task interface{}, err error) {
+				calls++
+				progress[task] = err
+			})
+			queue.Start(ctx)
+			for i := 0; i < 10; i++ {
+				Expect(queue.AddTask(i)).To(BeTrue())
+			}
+			Expect(queue.AddTask(nil)).To(BeTrue())
+			wg.Wait()
+		})
+		It("is called once per task with its error", func() {
+			Expect(calls).To(Equal(11))
+			for i := 0; i < 10; i++ {
+				Expect(progress).To(HaveKeyWithValue(i, BeNil()))
+			}
+			Expect(progress[nil]).To(Equal(errors.New("task is nil")))
+		})
+	})
 	When("worker func panics", func() {
 		BeforeEach(func() {
 			worker = func(ctx context.Context, task interface{}) error {
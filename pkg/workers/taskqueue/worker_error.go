@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package taskqueue
+
+import "errors"
+
+// WorkerError is the error of a failed task classified as transient, i.e. the task may succeed
+// if it's retried, e.g. after a 502 response, or as permanent, e.g. after a 404 response
+type WorkerError struct {
+	// Err is the task error
+	Err error
+	// Transient reports whether the task may succeed if it's retried
+	Transient bool
+}
+
+// NewWorkerError classifies err with transient, nil is returned for a nil err
+func NewWorkerError(err error, transient func(error) bool) error {
+	if err == nil {
+		return nil
+	}
+	return &WorkerError{Err: err, Transient: transient(err)}
+}
+
+func (e *WorkerError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *WorkerError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient reports whether err is a WorkerError classified as transient
+func IsTransient(err error) bool {
+	var wErr *WorkerError
+	return errors.As(err, &wErr) && wErr.Transient
+}
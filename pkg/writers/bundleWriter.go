@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gardener/docforge/pkg/manifest"
+)
+
+// BundleWriter is implementation of Writer interface that buffers the written blobs and on Close
+// writes them as a single document with the underlying Writer.
+// Blobs are ordered by the position of their nodes in Order, blobs of other nodes follow sorted
+// by path. Each blob is preceded by a heading with the node title or the blob path.
+type BundleWriter struct {
+	Writer Writer
+	// Name and Path of the bundle document
+	Name string
+	Path string
+	// Order lists the nodes in the order their blobs are bundled
+	Order []*manifest.Node
+
+	sections map[string]bundleSection
+	mux      sync.Mutex
+}
+
+type bundleSection struct {
+	blob []byte
+	node *manifest.Node
+}
+
+func (b *BundleWriter) Write(name, p string, docBlob []byte, node *manifest.Node) error {
+	if len(docBlob) == 0 {
+		return nil
+	}
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.sections == nil {
+		b.sections = map[string]bundleSection{}
+	}
+	b.sections[path.Join(p, name)] = bundleSection{blob: append([]byte{}, docBlob...), node: node}
+	return nil
+}
+
+// Close writes the bundle document
+func (b *BundleWriter) Close() error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	var keys []string
+	ordered := map[string]bool{}
+	for _, node := range b.Order {
+		key := node.NodePath()
+		if _, ok := b.sections[key]; ok && !ordered[key] {
+			keys = append(keys, key)
+			ordered[key] = true
+		}
+	}
+	var rest []string
+	for key := range b.sections {
+		if !ordered[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	var buf bytes.Buffer
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		section := b.sections[key]
+		fmt.Fprintf(&buf, "# %s\n\n", sectionTitle(key, section.node))
+		buf.Write(section.blob)
+		if !bytes.HasSuffix(section.blob, []byte("\n")) {
+			buf.WriteString("\n")
+		}
+	}
+	return b.Writer.Write(b.Name, b.Path, buf.Bytes(), nil)
+}
+
+// sectionTitle returns the node title property if set, otherwise the blob path
+func sectionTitle(key string, node *manifest.Node) string {
+	if node != nil {
+		if title, ok := node.Properties["title"].(string); ok && strings.TrimSpace(title) != "" {
+			return title
+		}
+	}
+	return key
+}
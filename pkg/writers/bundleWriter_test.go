@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"testing"
+
+	"github.com/gardener/docforge/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundleWriter(t *testing.T) {
+	intro := &manifest.Node{Type: "file", FileType: manifest.FileType{File: "intro.md"}, Properties: map[string]interface{}{"title": "Introduction"}}
+	setup := &manifest.Node{Type: "file", Path: "guides", FileType: manifest.FileType{File: "setup.md"}}
+	usage := &manifest.Node{Type: "file", Path: "guides", FileType: manifest.FileType{File: "usage.md"}, Properties: map[string]interface{}{"title": "Usage"}}
+	mem := &memWriter{}
+	w := &BundleWriter{
+		Writer: mem,
+		Name:   "bundle.md",
+		Path:   "print",
+		Order:  []*manifest.Node{intro, setup, usage},
+	}
+
+	assert.NoError(t, w.Write("usage.md", "guides", []byte("Run it.\n"), usage))
+	assert.NoError(t, w.Write("extra.md", "appendix", []byte("Extra."), nil))
+	assert.NoError(t, w.Write("intro.md", "", []byte("Hello.\n"), intro))
+	assert.NoError(t, w.Write("setup.md", "guides", nil, setup))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, "# Introduction\n\nHello.\n\n# Usage\n\nRun it.\n\n# appendix/extra.md\n\nExtra.\n", string(mem.blobs["print/bundle.md"]))
+}
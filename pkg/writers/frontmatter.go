@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StripFrontmatter returns a Transformer removing the leading YAML front matter block of markdown
+// blobs, i.e. blobs with .md or .markdown names or names without extension. The front matter
+// entries with keep keys are preserved in their order, the block is removed if there are none.
+func StripFrontmatter(keep ...string) Transformer {
+	return func(name, path string, blob []byte) ([]byte, error) {
+		if ext := strings.ToLower(filepath.Ext(name)); ext != "" && ext != ".md" && ext != ".markdown" {
+			return blob, nil
+		}
+		fm, content, ok := splitFrontmatter(blob)
+		if !ok {
+			return blob, nil
+		}
+		if len(keep) == 0 {
+			return content, nil
+		}
+		doc := &yaml.Node{}
+		if err := yaml.Unmarshal(fm, doc); err != nil {
+			return nil, fmt.Errorf("can't parse the front matter of %s : %w", filepath.Join(path, name), err)
+		}
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			return content, nil
+		}
+		mapping := doc.Content[0]
+		var kept []*yaml.Node
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if slices.Contains(keep, mapping.Content[i].Value) {
+				kept = append(kept, mapping.Content[i], mapping.Content[i+1])
+			}
+		}
+		if len(kept) == 0 {
+			return content, nil
+		}
+		mapping.Content = kept
+		out, err := yaml.Marshal(mapping)
+		if err != nil {
+			return nil, err
+		}
+		buf := bytes.Buffer{}
+		buf.WriteString("---\n")
+		buf.Write(out)
+		buf.WriteString("---\n")
+		buf.Write(content)
+		return buf.Bytes(), nil
+	}
+}
+
+// splitFrontmatter returns the front matter block of blob without its delimiters and the content after it
+func splitFrontmatter(blob []byte) ([]byte, []byte, bool) {
+	rest, ok := cutDelimiter(blob)
+	if !ok {
+		return nil, nil, false
+	}
+	for offset := 0; offset <= len(rest); {
+		line := rest[offset:]
+		if content, ok := cutDelimiter(line); ok {
+			return rest[:offset], content, true
+		}
+		i := bytes.IndexByte(line, '\n')
+		if i < 0 {
+			break
+		}
+		offset += i + 1
+	}
+	return nil, nil, false
+}
+
+// cutDelimiter returns the blob after a leading `---` front matter delimiter line
+func cutDelimiter(blob []byte) ([]byte, bool) {
+	rest, ok := bytes.CutPrefix(blob, []byte("---"))
+	if !ok {
+		return nil, false
+	}
+	rest = bytes.TrimLeft(rest, " \t")
+	if len(rest) == 0 {
+		return rest, true
+	}
+	if rest, ok = bytes.CutPrefix(rest, []byte("\r\n")); ok {
+		return rest, true
+	}
+	return bytes.CutPrefix(rest, []byte("\n"))
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"testing"
+)
+
+func TestStripFrontmatter(t *testing.T) {
+	md := "---\ntitle: Guide\nweight: 10\ndescription: How to\n---\n# Guide\n\n---\n\nText\n"
+	testCases := []struct {
+		name    string
+		file    string
+		keep    []string
+		blob    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "front matter removed",
+			file: "guide.md",
+			blob: md,
+			want: "# Guide\n\n---\n\nText\n",
+		},
+		{
+			name: "selected keys preserved in order",
+			file: "guide.md",
+			keep: []string{"description", "title"},
+			blob: md,
+			want: "---\ntitle: Guide\ndescription: How to\n---\n# Guide\n\n---\n\nText\n",
+		},
+		{
+			name: "no selected keys present",
+			file: "guide.md",
+			keep: []string{"aliases"},
+			blob: md,
+			want: "# Guide\n\n---\n\nText\n",
+		},
+		{
+			name: "CRLF delimiters",
+			file: "guide",
+			blob: "---\r\ntitle: Guide\r\n---\r\n# Guide\r\n",
+			want: "# Guide\r\n",
+		},
+		{
+			name: "no front matter",
+			file: "guide.md",
+			blob: "# Guide\n\n---\n\nText\n",
+			want: "# Guide\n\n---\n\nText\n",
+		},
+		{
+			name: "unterminated front matter",
+			file: "guide.md",
+			blob: "---\ntitle: Guide\n# Guide\n",
+			want: "---\ntitle: Guide\n# Guide\n",
+		},
+		{
+			name: "other files",
+			file: "config.yaml",
+			blob: "---\nkey: value\n---\n",
+			want: "---\nkey: value\n---\n",
+		},
+		{
+			name:    "malformed front matter",
+			file:    "guide.md",
+			keep:    []string{"title"},
+			blob:    "---\ntitle: [a\n---\n# Guide\n",
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := StripFrontmatter(tc.keep...)(tc.file, "docs", []byte(tc.blob))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -6,9 +6,16 @@ package writers
 
 import (
 	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/gardener/docforge/pkg/manifest"
 	"gopkg.in/yaml.v3"
@@ -19,8 +26,45 @@ type FSWriter struct {
 	Root string
 	Ext  string
 	Hugo bool
+	// Transformers are applied in order to non-empty blobs before they are written
+	Transformers []Transformer
+	// WriteEmpty enables writing empty blobs as empty files (e.g. .nojekyll),
+	// by default nothing is written for them
+	WriteEmpty bool
+	// Gzip enables writing a gzip-compressed name.gz sibling of each file,
+	// except for files with already compressed extensions
+	Gzip bool
+	// HashNames enables appending a short content hash to the file names before the extension
+	// for cache-busting, HashedNames maps the written names to the hashed ones
+	HashNames bool
+	// HashExclude are the extensions of files written with their names if HashNames is set,
+	// DefaultHashExclude is used if it's nil
+	HashExclude []string
+	// VersionPrefix is a directory below Root prepended to the paths of all writes, e.g. v1.2
+	VersionPrefix string
+	// NormalizeLineEndings enables replacing CRLF line endings with LF in text files
+	NormalizeLineEndings bool
+	// StripBOM enables removing a leading UTF-8 byte order mark from text files
+	StripBOM bool
+	// TextExtensions are the extensions of the files normalized by NormalizeLineEndings and StripBOM,
+	// DefaultTextExtensions is used if it's nil
+	TextExtensions []string
+	hashed         map[string]string
+	muxHashed      sync.RWMutex
 }
 
+// DefaultHashExclude are the extensions of documents that are referenced by their names
+var DefaultHashExclude = []string{".md", ".html", ".htm"}
+
+// DefaultTextExtensions are the extensions of the text files normalized by default
+var DefaultTextExtensions = []string{".md", ".html", ".htm", ".txt", ".yaml", ".yml", ".json", ".xml", ".svg", ".css", ".js", ".csv"}
+
+// utf8BOM is the UTF-8 encoded byte order mark
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Write writes docBlob to the file name in the directory path below Root.
+// The directory is created if needed. An empty name only creates the directory
+// and empty blobs are skipped unless WriteEmpty is set.
 func (f *FSWriter) Write(name, path string, docBlob []byte, node *manifest.Node) error {
 	//generate _index.md content
 	if f.Hugo && name == "_index.md" && node != nil && node.Frontmatter != nil && docBlob == nil {
@@ -34,10 +78,55 @@ func (f *FSWriter) Write(name, path string, docBlob []byte, node *manifest.Node)
 		_, _ = buf.Write([]byte("---\n"))
 		docBlob = buf.Bytes()
 	}
-	p := filepath.Join(f.Root, path)
-	if len(docBlob) == 0 {
+	p := filepath.Join(f.Root, f.VersionPrefix, path)
+	if name == "" {
+		return os.MkdirAll(p, os.ModePerm)
+	}
+	if len(docBlob) == 0 && !f.WriteEmpty {
 		return nil
 	}
+	for _, transform := range f.Transformers {
+		if len(docBlob) == 0 {
+			break
+		}
+		var err error
+		if docBlob, err = transform(name, path, docBlob); err != nil {
+			return fmt.Errorf("error transforming %s: %w", filepath.Join(p, name), err)
+		}
+	}
+	if err := os.MkdirAll(p, os.ModePerm); err != nil {
+		return err
+	}
+	if len(f.Ext) > 0 {
+		name = fmt.Sprintf("%s.%s", name, f.Ext)
+	}
+	docBlob = f.normalize(name, docBlob)
+	if f.HashNames && !f.hashExcluded(name) {
+		name = f.hash(name, filepath.Join(f.VersionPrefix, path), docBlob)
+	}
+	filePath := filepath.Join(p, name)
+	if err := writeFile(filePath, bytes.NewReader(docBlob)); err != nil {
+		return fmt.Errorf("error writing %s: %v", filePath, err)
+	}
+	if f.Gzip && !compressed(name) {
+		if err := writeGzipFile(filePath, docBlob); err != nil {
+			return fmt.Errorf("error writing %s.gz: %v", filePath, err)
+		}
+	}
+	return nil
+}
+
+// WriteStream copies the content of r to a file. If Transformers are configured
+// the content is read in memory and written with Write, as well as if Gzip, HashNames or a normalization is set.
+func (f *FSWriter) WriteStream(name, path string, r io.Reader, node *manifest.Node) error {
+	if len(f.Transformers) > 0 || f.Gzip || f.HashNames || f.NormalizeLineEndings || f.StripBOM {
+		docBlob, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return f.Write(name, path, docBlob, node)
+	}
+	p := filepath.Join(f.Root, f.VersionPrefix, path)
 	if err := os.MkdirAll(p, os.ModePerm); err != nil {
 		return err
 	}
@@ -45,8 +134,170 @@ func (f *FSWriter) Write(name, path string, docBlob []byte, node *manifest.Node)
 		name = fmt.Sprintf("%s.%s", name, f.Ext)
 	}
 	filePath := filepath.Join(p, name)
-	if err := os.WriteFile(filePath, docBlob, 0644); err != nil {
+	if err := writeFile(filePath, r); err != nil {
 		return fmt.Errorf("error writing %s: %v", filePath, err)
 	}
 	return nil
 }
+
+// Written checks if a file with name was written at path. Files are written
+// to a PartFileSuffix file first, so an existing file is always complete.
+func (f *FSWriter) Written(name, path string) bool {
+	path = filepath.Join(f.VersionPrefix, path)
+	if len(f.Ext) > 0 {
+		name = fmt.Sprintf("%s.%s", name, f.Ext)
+	}
+	if hashed, ok := f.HashedNames()[filepath.ToSlash(filepath.Join(path, name))]; ok {
+		name = filepath.Base(hashed)
+	}
+	info, err := os.Stat(filepath.Join(f.Root, path, name))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// WrittenBlob checks if a file with name was written at path with the content of the git blob sha.
+// Files written with hashed names are never reported, their names depend on the content and
+// HashedNames knows only the files written by this FSWriter. Transformers and normalizations
+// changing the content make the check fail as well.
+func (f *FSWriter) WrittenBlob(name, path, sha string) bool {
+	if len(f.Ext) > 0 {
+		name = fmt.Sprintf("%s.%s", name, f.Ext)
+	}
+	if f.HashNames && !f.hashExcluded(name) {
+		return false
+	}
+	content, err := os.ReadFile(filepath.Join(f.Root, f.VersionPrefix, path, name))
+	return err == nil && gitBlobSHA(content) == sha
+}
+
+// gitBlobSHA computes the SHA of content as a git blob object
+func gitBlobSHA(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashedNames returns the slash-delimited paths of the files written with hashed names
+// relative to Root mapped to the paths with the hashed names
+func (f *FSWriter) HashedNames() map[string]string {
+	f.muxHashed.RLock()
+	defer f.muxHashed.RUnlock()
+	names := make(map[string]string, len(f.hashed))
+	for name, hashed := range f.hashed {
+		names[name] = hashed
+	}
+	return names
+}
+
+// normalize applies the configured line ending and byte order mark normalization to the content of text files
+func (f *FSWriter) normalize(name string, content []byte) []byte {
+	if !f.NormalizeLineEndings && !f.StripBOM || !f.isText(name) {
+		return content
+	}
+	if f.StripBOM {
+		content = bytes.TrimPrefix(content, utf8BOM)
+	}
+	if f.NormalizeLineEndings {
+		content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	}
+	return content
+}
+
+// isText checks if a file name has a text extension
+func (f *FSWriter) isText(name string) bool {
+	extensions := f.TextExtensions
+	if extensions == nil {
+		extensions = DefaultTextExtensions
+	}
+	ext := filepath.Ext(name)
+	for _, e := range extensions {
+		if strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// hashExcluded checks if a file name has an extension that is not hashed
+func (f *FSWriter) hashExcluded(name string) bool {
+	exclude := f.HashExclude
+	if exclude == nil {
+		exclude = DefaultHashExclude
+	}
+	ext := filepath.Ext(name)
+	for _, e := range exclude {
+		if strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// hash returns name with the short content hash inserted before the extension and records it
+func (f *FSWriter) hash(name, path string, content []byte) string {
+	sum := sha256.Sum256(content)
+	ext := filepath.Ext(name)
+	hashed := fmt.Sprintf("%s.%s%s", strings.TrimSuffix(name, ext), hex.EncodeToString(sum[:])[:8], ext)
+	f.muxHashed.Lock()
+	defer f.muxHashed.Unlock()
+	if f.hashed == nil {
+		f.hashed = map[string]string{}
+	}
+	f.hashed[filepath.ToSlash(filepath.Join(path, name))] = filepath.ToSlash(filepath.Join(path, hashed))
+	return hashed
+}
+
+// PartFileSuffix is the suffix of files that are being written
+const PartFileSuffix = ".part"
+
+// writeFile copies the content of r to a PartFileSuffix file and renames it to filePath on success
+func writeFile(filePath string, r io.Reader) error {
+	partPath := filePath + PartFileSuffix
+	file, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(file, r); err != nil {
+		_ = file.Close()
+		_ = os.Remove(partPath)
+		return err
+	}
+	if err = file.Close(); err != nil {
+		_ = os.Remove(partPath)
+		return err
+	}
+	return os.Rename(partPath, filePath)
+}
+
+// compressedExtensions are the extensions of files that gain nothing from gzip compression
+var compressedExtensions = map[string]bool{
+	".gz": true, ".tgz": true, ".zip": true, ".bz2": true, ".xz": true, ".zst": true, ".br": true, ".7z": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".avif": true,
+	".woff": true, ".woff2": true, ".mp3": true, ".mp4": true, ".webm": true, ".pdf": true,
+}
+
+// compressed checks if a file name has an already compressed extension
+func compressed(name string) bool {
+	return compressedExtensions[strings.ToLower(filepath.Ext(name))]
+}
+
+// writeGzipFile writes the gzip-compressed content to filePath.gz with the mode of filePath
+func writeGzipFile(filePath string, content []byte) error {
+	buf := bytes.Buffer{}
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(content); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	gzPath := filePath + ".gz"
+	if err := writeFile(gzPath, &buf); err != nil {
+		return err
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	return os.Chmod(gzPath, info.Mode())
+}
@@ -5,11 +5,16 @@
 package writers
 
 import (
+	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/gardener/docforge/pkg/manifest"
@@ -79,3 +84,420 @@ func TestWrite(t *testing.T) {
 		})
 	}
 }
+
+func TestWritten(t *testing.T) {
+	testPath := filepath.Join(os.TempDir(), fmt.Sprintf("test%s", uuid.New().String()))
+	defer func() {
+		if err := os.RemoveAll(testPath); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}()
+	fs := &FSWriter{Root: testPath}
+	if fs.Written("test.md", "a/b") {
+		t.Errorf("expected test.md not to be written")
+	}
+	if err := fs.Write("test.md", "a/b", []byte("# Test"), &manifest.Node{}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !fs.Written("test.md", "a/b") {
+		t.Errorf("expected test.md to be written")
+	}
+	if _, err := os.Stat(filepath.Join(testPath, "a/b", "test.md"+PartFileSuffix)); !os.IsNotExist(err) {
+		t.Errorf("expected part file to be renamed")
+	}
+}
+
+func TestWrittenBlob(t *testing.T) {
+	testPath := filepath.Join(os.TempDir(), fmt.Sprintf("test%s", uuid.New().String()))
+	defer func() {
+		if err := os.RemoveAll(testPath); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}()
+	// the git blob SHAs of "# Test" and "# Other"
+	testSHA, otherSHA := "21e60f8358c6175f2efbbe34808a4d99d12d18ee", "25bb92f1072fcbbe0f21626db8648dc7d28555ec"
+	fs := &FSWriter{Root: testPath}
+	if fs.WrittenBlob("test.md", "a/b", testSHA) {
+		t.Errorf("expected test.md not to be written")
+	}
+	if err := fs.Write("test.md", "a/b", []byte("# Test"), &manifest.Node{}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !fs.WrittenBlob("test.md", "a/b", testSHA) {
+		t.Errorf("expected test.md to be written")
+	}
+	if fs.WrittenBlob("test.md", "a/b", otherSHA) {
+		t.Errorf("expected test.md not to be written with other content")
+	}
+	hashing := &FSWriter{Root: testPath, HashNames: true, HashExclude: []string{}}
+	if hashing.WrittenBlob("test.md", "a/b", testSHA) {
+		t.Errorf("expected test.md not to be reported with hashed names")
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	testCases := []struct {
+		name       string
+		writeEmpty bool
+		wantFile   bool
+	}{
+		{
+			name:       "empty writes enabled",
+			writeEmpty: true,
+			wantFile:   true,
+		},
+		{
+			name:       "empty writes disabled",
+			writeEmpty: false,
+			wantFile:   false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testPath := filepath.Join(os.TempDir(), fmt.Sprintf("test%s", uuid.New().String()))
+			defer func() {
+				if err := os.RemoveAll(testPath); err != nil {
+					t.Fatalf("%v\n", err)
+				}
+			}()
+			fs := &FSWriter{Root: testPath, WriteEmpty: tc.writeEmpty}
+
+			if err := fs.Write(".nojekyll", "site", []byte{}, &manifest.Node{}); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			info, err := os.Stat(filepath.Join(testPath, "site", ".nojekyll"))
+			if tc.wantFile && (err != nil || info.Size() != 0) {
+				t.Errorf("expected empty file to be written: %v", err)
+			}
+			if !tc.wantFile && !os.IsNotExist(err) {
+				t.Errorf("expected empty file not to be written")
+			}
+		})
+	}
+}
+
+func TestWriteGzip(t *testing.T) {
+	testCases := []struct {
+		name     string
+		file     string
+		wantGzip bool
+	}{
+		{
+			name:     "compressible file",
+			file:     "doc.md",
+			wantGzip: true,
+		},
+		{
+			name:     "already compressed file",
+			file:     "image.PNG",
+			wantGzip: false,
+		},
+	}
+	content := []byte("# Title\n\nSome content to compress.\n")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testPath := filepath.Join(os.TempDir(), fmt.Sprintf("test%s", uuid.New().String()))
+			defer func() {
+				if err := os.RemoveAll(testPath); err != nil {
+					t.Fatalf("%v\n", err)
+				}
+			}()
+			fs := &FSWriter{Root: testPath, Gzip: true}
+
+			if err := fs.WriteStream(tc.file, "site", bytes.NewReader(content), &manifest.Node{}); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			filePath := filepath.Join(testPath, "site", tc.file)
+			info, err := os.Stat(filePath)
+			if err != nil {
+				t.Fatalf("expected file to be written: %v", err)
+			}
+			gzFile, err := os.Open(filePath + ".gz")
+			if !tc.wantGzip {
+				if !os.IsNotExist(err) {
+					t.Errorf("expected no gzip file to be written")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected gzip file to be written: %v", err)
+			}
+			defer gzFile.Close()
+			gzInfo, err := gzFile.Stat()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if gzInfo.Mode() != info.Mode() {
+				t.Errorf("expected gzip file mode %v, was %v", info.Mode(), gzInfo.Mode())
+			}
+			zr, err := gzip.NewReader(gzFile)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			got, err := io.ReadAll(zr)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("expected decompressed content %q, was %q", content, got)
+			}
+		})
+	}
+}
+
+func TestWriteHashNames(t *testing.T) {
+	testPath := filepath.Join(os.TempDir(), fmt.Sprintf("test%s", uuid.New().String()))
+	defer func() {
+		if err := os.RemoveAll(testPath); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}()
+	fs := &FSWriter{Root: testPath, HashNames: true}
+	css := []byte("body { color: red; }")
+	writes := []struct {
+		name    string
+		path    string
+		content []byte
+	}{
+		{name: "style.css", path: "assets", content: css},
+		{name: "copy.css", path: "assets", content: css},
+		{name: "LICENSE", path: "", content: []byte("license")},
+		{name: "doc.md", path: "docs", content: []byte("# Doc")},
+		{name: "index.html", path: "", content: []byte("<html></html>")},
+	}
+	for _, w := range writes {
+		if err := fs.Write(w.name, w.path, w.content, &manifest.Node{}); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	hashed := fs.HashedNames()
+	if len(hashed) != 3 {
+		t.Errorf("expected 3 hashed names, was %v", hashed)
+	}
+	style, copied := hashed["assets/style.css"], hashed["assets/copy.css"]
+	if !strings.HasPrefix(style, "assets/style.") || !strings.HasSuffix(style, ".css") || len(style) != len("assets/style.12345678.css") {
+		t.Errorf("unexpected hashed name %s", style)
+	}
+	if strings.TrimPrefix(style, "assets/style") != strings.TrimPrefix(copied, "assets/copy") {
+		t.Errorf("expected the same hash for identical content, was %s and %s", style, copied)
+	}
+	if license := hashed["LICENSE"]; !strings.HasPrefix(license, "LICENSE.") || len(license) != len("LICENSE.12345678") {
+		t.Errorf("unexpected hashed name %s", license)
+	}
+	for _, name := range []string{"assets/" + filepath.Base(style), hashed["LICENSE"], "docs/doc.md", "index.html"} {
+		if _, err := os.Stat(filepath.Join(testPath, name)); err != nil {
+			t.Errorf("expected %s to be written: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(testPath, "assets", "style.css")); !os.IsNotExist(err) {
+		t.Errorf("expected style.css not to be written")
+	}
+	if !fs.Written("style.css", "assets") {
+		t.Errorf("expected style.css to be reported as written")
+	}
+}
+
+func TestWriteVersionPrefix(t *testing.T) {
+	testCases := []struct {
+		name          string
+		versionPrefix string
+		wantDir       string
+	}{
+		{
+			name:          "version prefix",
+			versionPrefix: "v1.2",
+			wantDir:       filepath.Join("v1.2", "docs"),
+		},
+		{
+			name:          "no version prefix",
+			versionPrefix: "",
+			wantDir:       "docs",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testPath := filepath.Join(os.TempDir(), fmt.Sprintf("test%s", uuid.New().String()))
+			defer func() {
+				if err := os.RemoveAll(testPath); err != nil {
+					t.Fatalf("%v\n", err)
+				}
+			}()
+			fs := &FSWriter{Root: testPath, VersionPrefix: tc.versionPrefix}
+
+			if err := fs.Write("doc.md", "docs", []byte("# Doc"), &manifest.Node{}); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err := fs.WriteStream("image.png", "docs", bytes.NewReader([]byte("png")), &manifest.Node{}); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			for _, name := range []string{"doc.md", "image.png"} {
+				if _, err := os.Stat(filepath.Join(testPath, tc.wantDir, name)); err != nil {
+					t.Errorf("expected %s to be written in %s: %v", name, tc.wantDir, err)
+				}
+				if !fs.Written(name, "docs") {
+					t.Errorf("expected %s to be reported as written", name)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteNormalize(t *testing.T) {
+	content := []byte("\xEF\xBB\xBF# Title\r\n\r\nText\r\n")
+	testCases := []struct {
+		name   string
+		writer *FSWriter
+		file   string
+		want   []byte
+	}{
+		{
+			name:   "normalization disabled",
+			writer: &FSWriter{},
+			file:   "doc.md",
+			want:   content,
+		},
+		{
+			name:   "line endings and BOM",
+			writer: &FSWriter{NormalizeLineEndings: true, StripBOM: true},
+			file:   "doc.md",
+			want:   []byte("# Title\n\nText\n"),
+		},
+		{
+			name:   "line endings only",
+			writer: &FSWriter{NormalizeLineEndings: true},
+			file:   "doc.md",
+			want:   []byte("\xEF\xBB\xBF# Title\n\nText\n"),
+		},
+		{
+			name:   "BOM only",
+			writer: &FSWriter{StripBOM: true},
+			file:   "doc.md",
+			want:   []byte("# Title\r\n\r\nText\r\n"),
+		},
+		{
+			name:   "binary file",
+			writer: &FSWriter{NormalizeLineEndings: true, StripBOM: true},
+			file:   "image.png",
+			want:   content,
+		},
+		{
+			name:   "custom text extensions",
+			writer: &FSWriter{NormalizeLineEndings: true, StripBOM: true, TextExtensions: []string{".TXT"}},
+			file:   "notes.txt",
+			want:   []byte("# Title\n\nText\n"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testPath := filepath.Join(os.TempDir(), fmt.Sprintf("test%s", uuid.New().String()))
+			defer func() {
+				if err := os.RemoveAll(testPath); err != nil {
+					t.Fatalf("%v\n", err)
+				}
+			}()
+			fs := tc.writer
+			fs.Root = testPath
+			if err := fs.Write(tc.file, "written", content, &manifest.Node{}); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err := fs.WriteStream(tc.file, "streamed", bytes.NewReader(content), &manifest.Node{}); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			for _, dir := range []string{"written", "streamed"} {
+				got, err := os.ReadFile(filepath.Join(testPath, dir, tc.file))
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if !bytes.Equal(got, tc.want) {
+					t.Errorf("%s content %q, want %q", dir, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteDirectory(t *testing.T) {
+	testPath := filepath.Join(os.TempDir(), fmt.Sprintf("test%s", uuid.New().String()))
+	defer func() {
+		if err := os.RemoveAll(testPath); err != nil {
+			t.Fatalf("%v\n", err)
+		}
+	}()
+	fs := &FSWriter{Root: testPath}
+	if err := fs.Write("", "a/b", nil, nil); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(testPath, "a/b")); err != nil || !info.IsDir() {
+		t.Errorf("expected directory to be created: %v", err)
+	}
+}
+
+func TestWriteTransformers(t *testing.T) {
+	banner := func(name, path string, blob []byte) ([]byte, error) {
+		return append([]byte("<!-- generated "+path+"/"+name+" -->\n"), blob...), nil
+	}
+	upper := func(name, path string, blob []byte) ([]byte, error) {
+		return bytes.ToUpper(blob), nil
+	}
+	failing := func(name, path string, blob []byte) ([]byte, error) {
+		return nil, errors.New("transform failed")
+	}
+	testCases := []struct {
+		name         string
+		transformers []Transformer
+		wantErr      string
+		wantContent  string
+	}{
+		{
+			name:         "chained transformers",
+			transformers: []Transformer{banner, upper},
+			wantContent:  "<!-- GENERATED A/B/TEST.MD -->\n# TEST",
+		},
+		{
+			name:         "failing transformer",
+			transformers: []Transformer{banner, failing},
+			wantErr:      "transform failed",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testPath := filepath.Join(os.TempDir(), fmt.Sprintf("test%s", uuid.New().String()))
+			defer func() {
+				if err := os.RemoveAll(testPath); err != nil {
+					t.Fatalf("%v\n", err)
+				}
+			}()
+			fs := &FSWriter{
+				Root:         testPath,
+				Transformers: tc.transformers,
+			}
+			fPath := filepath.Join(testPath, "a/b", "test.md")
+
+			err := fs.Write("test.md", "a/b", []byte("# Test"), &manifest.Node{})
+
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("expected err %v != %v", tc.wantErr, err)
+				}
+				if _, err := os.Stat(fPath); !os.IsNotExist(err) {
+					t.Errorf("expected file not to be written")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			b, err := os.ReadFile(fPath)
+			if err != nil {
+				t.Fatalf("unexpected error opening file %v", err)
+			}
+			if string(b) != tc.wantContent {
+				t.Errorf("expected content %q != %q", tc.wantContent, string(b))
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/gardener/docforge/pkg/manifest"
+)
+
+// IndexFileName is the default name of the file listing the written outputs
+const IndexFileName = ".docforge-manifest.json"
+
+// IndexEntry describes a written blob
+type IndexEntry struct {
+	// Path of the blob relative to the writer root
+	Path string `json:"path"`
+	// Size of the blob in bytes
+	Size int `json:"size"`
+	// Node is the path of the node the blob is written for
+	Node string `json:"node,omitempty"`
+	// Source of the node content
+	Source []string `json:"source,omitempty"`
+}
+
+// IndexWriter is implementation of Writer interface that records the blobs written by the underlying
+// Writer and writes the records as JSON to IndexFile on Close
+type IndexWriter struct {
+	Writer    Writer
+	IndexFile string
+
+	entries []IndexEntry
+	mux     sync.Mutex
+}
+
+// NewIndexWriter creates an IndexWriter writing the index to IndexFileName in root
+func NewIndexWriter(w Writer, root string) *IndexWriter {
+	return &IndexWriter{
+		Writer:    w,
+		IndexFile: filepath.Join(root, IndexFileName),
+	}
+}
+
+func (i *IndexWriter) Write(name, p string, docBlob []byte, node *manifest.Node) error {
+	if err := i.Writer.Write(name, p, docBlob, node); err != nil {
+		return err
+	}
+	if len(docBlob) == 0 {
+		return nil
+	}
+	entry := IndexEntry{Path: path.Join(p, name), Size: len(docBlob)}
+	if node != nil {
+		entry.Node = node.NodePath()
+		if len(node.Source) > 0 {
+			entry.Source = append(entry.Source, node.Source)
+		}
+		entry.Source = append(entry.Source, node.MultiSource...)
+	}
+	i.mux.Lock()
+	defer i.mux.Unlock()
+	i.entries = append(i.entries, entry)
+	return nil
+}
+
+// Close writes the index sorted by path
+func (i *IndexWriter) Close() error {
+	i.mux.Lock()
+	defer i.mux.Unlock()
+	entries := append([]IndexEntry{}, i.entries...)
+	sort.Slice(entries, func(a, b int) bool { return entries[a].Path < entries[b].Path })
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(i.IndexFile), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(i.IndexFile, content, 0644)
+}
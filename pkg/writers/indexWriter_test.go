@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gardener/docforge/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexWriter(t *testing.T) {
+	root := t.TempDir()
+	mem := &memWriter{}
+	w := NewIndexWriter(mem, root)
+	node := &manifest.Node{
+		Type:     "file",
+		Path:     "docs",
+		FileType: manifest.FileType{File: "readme.md", Source: "https://github.com/gardener/docforge/blob/master/README.md"},
+	}
+
+	assert.NoError(t, w.Write("readme.md", "docs", []byte("# Readme"), node))
+	assert.NoError(t, w.Write("logo.png", "__resources", []byte("png"), nil))
+	assert.NoError(t, w.Write("empty.md", "docs", nil, nil))
+	failing := NewIndexWriter(&memWriter{err: errors.New("write failed")}, root)
+	assert.Error(t, failing.Write("failed.md", "docs", []byte("# Failed"), nil))
+	assert.NoError(t, w.Close())
+
+	content, err := os.ReadFile(filepath.Join(root, IndexFileName))
+	assert.NoError(t, err)
+	var entries []IndexEntry
+	assert.NoError(t, json.Unmarshal(content, &entries))
+	assert.Equal(t, []IndexEntry{
+		{Path: "__resources/logo.png", Size: 3},
+		{Path: "docs/readme.md", Size: 8, Node: "docs/readme.md", Source: []string{"https://github.com/gardener/docforge/blob/master/README.md"}},
+	}, entries)
+	assert.Equal(t, []byte("# Readme"), mem.blobs["docs/readme.md"])
+}
@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"github.com/gardener/docforge/pkg/manifest"
+	"github.com/hashicorp/go-multierror"
+)
+
+// MultiWriter is implementation of Writer interface that writes blobs to several writers
+type MultiWriter struct {
+	Writers []Writer
+	// FailFast stops writing on the first failing writer, otherwise all writers
+	// are called and their errors are aggregated
+	FailFast bool
+}
+
+// NewMultiWriter creates a MultiWriter that aggregates the errors of writers
+func NewMultiWriter(writers ...Writer) *MultiWriter {
+	return &MultiWriter{Writers: writers}
+}
+
+func (m *MultiWriter) Write(name, path string, docBlob []byte, node *manifest.Node) error {
+	var errs *multierror.Error
+	for _, w := range m.Writers {
+		if err := w.Write(name, path, docBlob, node); err != nil {
+			if m.FailFast {
+				return err
+			}
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
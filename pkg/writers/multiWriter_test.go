@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2023 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gardener/docforge/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+type memWriter struct {
+	blobs map[string][]byte
+	err   error
+}
+
+func (m *memWriter) Write(name, path string, docBlob []byte, _ *manifest.Node) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.blobs == nil {
+		m.blobs = map[string][]byte{}
+	}
+	m.blobs[path+"/"+name] = docBlob
+	return nil
+}
+
+func TestMultiWriter(t *testing.T) {
+	testCases := []struct {
+		name      string
+		withError bool
+		failFast  bool
+		wantErrs  []string
+		wantLast  bool
+	}{
+		{name: "all writers succeed", wantLast: true},
+		{name: "errors are aggregated", withError: true, wantErrs: []string{"first failed", "second failed"}, wantLast: true},
+		{name: "fail fast", withError: true, failFast: true, wantErrs: []string{"first failed"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			first, last := &memWriter{}, &memWriter{}
+			writers := []Writer{first}
+			if tc.withError {
+				writers = append(writers, &memWriter{err: errors.New("first failed")}, &memWriter{err: errors.New("second failed")})
+			}
+			writers = append(writers, last)
+			w := NewMultiWriter(writers...)
+			w.FailFast = tc.failFast
+
+			err := w.Write("test.md", "a/b", []byte("# Test"), &manifest.Node{})
+
+			if len(tc.wantErrs) == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+			for _, e := range tc.wantErrs {
+				assert.Contains(t, err.Error(), e)
+			}
+			if tc.failFast {
+				assert.NotContains(t, err.Error(), "second failed")
+			}
+			assert.Equal(t, []byte("# Test"), first.blobs["a/b/test.md"])
+			if tc.wantLast {
+				assert.Equal(t, []byte("# Test"), last.blobs["a/b/test.md"])
+			} else {
+				assert.Nil(t, last.blobs)
+			}
+		})
+	}
+}
@@ -4,13 +4,37 @@
 
 package writers
 
-import "github.com/gardener/docforge/pkg/manifest"
+import (
+	"io"
+
+	"github.com/gardener/docforge/pkg/manifest"
+)
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate -header ../../license_prefix.txt
 
-// Writer writes blobs with name to a given path
+// Writer writes blobs with name to a given path.
+// Implementations may post-process blobs with Transformer hooks before they are persisted.
 //
 //counterfeiter:generate . Writer
 type Writer interface {
 	Write(name, path string, resourceContent []byte, node *manifest.Node) error
 }
+
+// StreamWriter is implemented by writers that can write a blob from a reader without buffering it
+type StreamWriter interface {
+	WriteStream(name, path string, r io.Reader, node *manifest.Node) error
+}
+
+// CompletionChecker is implemented by writers that can tell whether a blob was already written completely
+type CompletionChecker interface {
+	Written(name, path string) bool
+}
+
+// BlobChecker is implemented by writers that can tell whether a blob with the given git blob SHA was already written
+type BlobChecker interface {
+	WrittenBlob(name, path, sha string) bool
+}
+
+// Transformer post-processes a blob with name and path before it is written.
+// Returning an error aborts the write.
+type Transformer func(name, path string, blob []byte) ([]byte, error)